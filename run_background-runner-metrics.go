@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BackgroundRunnerMetrics records the behavior of a BackgroundRunner.
+type BackgroundRunnerMetrics interface {
+	// Accepted is called when a run was accepted and becomes active.
+	Accepted()
+	// Completed is called when an active run completed or was dropped.
+	Completed()
+	// Failed is called when a run returned an error.
+	Failed()
+	// Skipped is called when a run was skipped because the runner is busy.
+	Skipped()
+}
+
+// NewBackgroundRunnerMetrics creates BackgroundRunnerMetrics backed by Prometheus collectors registered with the given registerer.
+// Already registered equal collectors are reused.
+func NewBackgroundRunnerMetrics(
+	registerer prometheus.Registerer,
+	namespace string,
+	subsystem string,
+) BackgroundRunnerMetrics {
+	return &backgroundRunnerMetrics{
+		active: registerOrExisting(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "background_runner_active",
+			Help:      "Number of active background runs",
+		})),
+		failed: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "background_runner_failed_total",
+			Help:      "Total number of failed background runs",
+		})),
+		skipped: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "background_runner_skipped_total",
+			Help:      "Total number of skipped background runs",
+		})),
+	}
+}
+
+type backgroundRunnerMetrics struct {
+	active  prometheus.Gauge
+	failed  prometheus.Counter
+	skipped prometheus.Counter
+}
+
+func (b *backgroundRunnerMetrics) Accepted() {
+	b.active.Inc()
+}
+
+func (b *backgroundRunnerMetrics) Completed() {
+	b.active.Dec()
+}
+
+func (b *backgroundRunnerMetrics) Failed() {
+	b.failed.Inc()
+}
+
+func (b *backgroundRunnerMetrics) Skipped() {
+	b.skipped.Inc()
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("BackgroundRunnerMetrics", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var registry *prometheus.Registry
+	var runner run.BackgroundRunnerWaiter
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		registry = prometheus.NewRegistry()
+		runner = run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{
+			Mode:         run.BackgroundRunnerModeSkip,
+			ErrorHandler: func(ctx context.Context, err error) {},
+			Metrics:      run.NewBackgroundRunnerMetrics(registry, "ns", "sub"),
+		})
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("records active, skipped and failed runs", func() {
+		release := make(chan struct{})
+		Expect(runner.Run(func(ctx context.Context) error {
+			<-release
+			return stderrors.New("banana")
+		})).To(BeNil())
+		Expect(metricValue(registry, "ns_sub_background_runner_active", nil)).To(Equal(1.0))
+
+		Expect(runner.Run(func(ctx context.Context) error { return nil })).To(BeNil())
+		Expect(
+			metricValue(registry, "ns_sub_background_runner_skipped_total", nil),
+		).To(Equal(1.0))
+
+		close(release)
+		Expect(runner.Wait(ctx)).To(BeNil())
+		Expect(metricValue(registry, "ns_sub_background_runner_active", nil)).To(Equal(0.0))
+		Expect(
+			metricValue(registry, "ns_sub_background_runner_failed_total", nil),
+		).To(Equal(1.0))
+	})
+})
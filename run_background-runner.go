@@ -1,4 +1,4 @@
-// Copyright (c) 2023-2025 Benjamin Borbe All rights reserved.
+// Copyright (c) 2023-2026 Benjamin Borbe All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
@@ -6,35 +6,251 @@ package run
 
 import (
 	"context"
+	"sync"
+	"time"
 
-	"github.com/golang/glog"
+	"github.com/bborbe/errors"
 )
 
-// BackgroundRunner executes functions in background goroutines with parallel execution prevention.
+// BackgroundRunner executes functions in background goroutines.
 // It embeds FuncRunner and inherits the Run method, executing functions asynchronously.
-// It ensures that only one instance of a function runs at a time, skipping subsequent calls if already running.
+// How calls are handled while a function is still running depends on the BackgroundRunnerMode.
 type BackgroundRunner interface {
 	FuncRunner
 }
 
+// BackgroundRunnerWaiter is a BackgroundRunner that additionally allows waiting for its background runs.
+type BackgroundRunnerWaiter interface {
+	BackgroundRunner
+	// Wait blocks until no background run is active or the context is canceled.
+	Wait(ctx context.Context) error
+	// Active returns the number of accepted background runs that have not completed yet,
+	// including runs that are queued or waiting for a free slot.
+	Active() int
+}
+
+// BackgroundRunnerMode defines how a BackgroundRunner handles calls while it is busy.
+type BackgroundRunnerMode int
+
+const (
+	// BackgroundRunnerModeSkip skips the call if a function is already running.
+	BackgroundRunnerModeSkip BackgroundRunnerMode = iota
+	// BackgroundRunnerModeQueue queues the call and runs all functions one after another.
+	BackgroundRunnerModeQueue
+	// BackgroundRunnerModeParallel runs the functions in parallel, limited by MaxParallel.
+	BackgroundRunnerModeParallel
+)
+
+// BackgroundRunnerOptions configures a BackgroundRunner.
+type BackgroundRunnerOptions struct {
+	// Mode defines how calls are handled while the runner is busy.
+	Mode BackgroundRunnerMode
+	// MaxParallel limits the number of parallel runs in BackgroundRunnerModeParallel.
+	// Zero or less means no limit.
+	MaxParallel int
+	// ErrorHandler is called with the error of each failed run.
+	// If nil, errors are logged as warnings.
+	ErrorHandler func(ctx context.Context, err error)
+	// Metrics is an optional recorder for active, failed and skipped runs.
+	Metrics BackgroundRunnerMetrics
+	// Logger is used for logging. If nil, the DefaultLogger is used.
+	Logger Logger
+	// RecoverPanics recovers panics of the runs and handles them as PanicError like other errors.
+	// Panics are also recovered if the context was created with ContextWithPanicRecovery.
+	RecoverPanics bool
+}
+
 // NewBackgroundRunner creates a new BackgroundRunner that uses the provided context for all background operations.
 // The returned runner will skip parallel executions and log the results of background operations.
 func NewBackgroundRunner(ctx context.Context) BackgroundRunner {
-	parallelSkipper := NewParallelSkipper()
-	return FuncRunnerFunc(func(runFunc Func) error {
-		go func() {
-			action := parallelSkipper.SkipParallel(func(ctx context.Context) error {
-				if err := runFunc(ctx); err != nil {
-					return err
-				}
-				return nil
-			})
-			glog.V(3).Infof("run started")
-			if err := action(ctx); err != nil {
-				glog.Warningf("run failed: %v", err)
+	return NewBackgroundRunnerWithOptions(ctx, BackgroundRunnerOptions{
+		Mode: BackgroundRunnerModeSkip,
+	})
+}
+
+// NewBackgroundRunnerWithOptions creates a new BackgroundRunner that uses the provided context for all background operations.
+// Canceling the context shuts the runner down: queued runs are dropped and Wait can be used to wait for running ones.
+func NewBackgroundRunnerWithOptions(
+	ctx context.Context,
+	options BackgroundRunnerOptions,
+) BackgroundRunnerWaiter {
+	b := &backgroundRunner{
+		ctx:     ctx,
+		options: options,
+	}
+	if options.Mode == BackgroundRunnerModeParallel && options.MaxParallel > 0 {
+		b.limit = make(chan struct{}, options.MaxParallel)
+	}
+	return b
+}
+
+type backgroundRunner struct {
+	ctx     context.Context
+	options BackgroundRunnerOptions
+	limit   chan struct{}
+
+	mux     sync.Mutex
+	active  int
+	running bool
+	queue   []Func
+	idle    chan struct{}
+}
+
+func (b *backgroundRunner) Run(runFunc Func) error {
+	if runFunc == nil {
+		return errors.New(b.ctx, "nil function")
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	switch b.options.Mode {
+	case BackgroundRunnerModeQueue:
+		b.add()
+		if b.running {
+			b.logger().Debug(b.ctx, "already running => queue", "func", funcName(runFunc))
+			b.queue = append(b.queue, runFunc)
+			return nil
+		}
+		b.running = true
+		goLabeled(b.ctx, "BackgroundRunner", func() { b.runSequential(runFunc) })
+	case BackgroundRunnerModeParallel:
+		b.add()
+		goLabeled(b.ctx, "BackgroundRunner", func() { b.runParallel(runFunc) })
+	default:
+		if b.running {
+			b.logger().Debug(b.ctx, "skip => already running", "func", funcName(runFunc))
+			if b.options.Metrics != nil {
+				b.options.Metrics.Skipped()
 			}
-			glog.V(3).Infof("run completed")
-		}()
+			return nil
+		}
+		b.running = true
+		b.add()
+		goLabeled(b.ctx, "BackgroundRunner", func() { b.runSequential(runFunc) })
+	}
+	return nil
+}
+
+func (b *backgroundRunner) Wait(ctx context.Context) error {
+	b.mux.Lock()
+	if b.active == 0 {
+		b.mux.Unlock()
 		return nil
-	})
+	}
+	idle := b.idle
+	b.mux.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-idle:
+		return nil
+	}
+}
+
+func (b *backgroundRunner) Active() int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.active
+}
+
+func (b *backgroundRunner) runSequential(runFunc Func) {
+	for {
+		b.execute(runFunc)
+
+		b.mux.Lock()
+		b.done()
+		if len(b.queue) == 0 {
+			b.running = false
+			b.mux.Unlock()
+			return
+		}
+		runFunc = b.queue[0]
+		b.queue = b.queue[1:]
+		b.mux.Unlock()
+
+		if b.ctx.Err() != nil {
+			b.dropQueue()
+			return
+		}
+	}
+}
+
+// dropQueue discards all queued runs after the runner was canceled.
+func (b *backgroundRunner) dropQueue() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.logger().Debug(b.ctx, "canceled => drop queued runs", "count", len(b.queue)+1)
+	for range b.queue {
+		b.done()
+	}
+	b.queue = nil
+	b.running = false
+	b.done()
+}
+
+func (b *backgroundRunner) runParallel(runFunc Func) {
+	if b.limit != nil {
+		select {
+		case <-b.ctx.Done():
+			b.mux.Lock()
+			b.done()
+			b.mux.Unlock()
+			return
+		case b.limit <- struct{}{}:
+		}
+	}
+	b.execute(runFunc)
+	if b.limit != nil {
+		<-b.limit
+	}
+
+	b.mux.Lock()
+	b.done()
+	b.mux.Unlock()
+}
+
+func (b *backgroundRunner) execute(runFunc Func) {
+	name := funcName(runFunc)
+	b.logger().Debug(b.ctx, "run started", "func", name)
+	if b.options.RecoverPanics || PanicRecoveryFromContext(b.ctx) {
+		runFunc = CatchPanic(runFunc)
+	}
+	start := time.Now()
+	if err := runFunc(b.ctx); err != nil {
+		if b.options.Metrics != nil {
+			b.options.Metrics.Failed()
+		}
+		if b.options.ErrorHandler != nil {
+			b.options.ErrorHandler(b.ctx, err)
+		} else {
+			b.logger().Warn(b.ctx, "run failed", runFailedArgs(name, time.Since(start), err)...)
+		}
+	}
+	b.logger().Debug(b.ctx, "run completed", "func", name, "duration", time.Since(start))
+}
+
+func (b *backgroundRunner) logger() Logger {
+	return orDefaultLogger(b.options.Logger)
+}
+
+// add registers an accepted run, the caller must hold the lock.
+func (b *backgroundRunner) add() {
+	if b.active == 0 {
+		b.idle = make(chan struct{})
+	}
+	b.active++
+	if b.options.Metrics != nil {
+		b.options.Metrics.Accepted()
+	}
+}
+
+// done unregisters a completed run, the caller must hold the lock.
+func (b *backgroundRunner) done() {
+	b.active--
+	if b.options.Metrics != nil {
+		b.options.Metrics.Completed()
+	}
+	if b.active == 0 {
+		close(b.idle)
+	}
 }
@@ -6,7 +6,9 @@ package run_test
 
 import (
 	"context"
+	stderrors "errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -69,4 +71,131 @@ var _ = Describe("BackgroundRunner", func() {
 		// Should return quickly (well before the function completes)
 		Expect(time.Since(start)).To(BeNumerically("<", 50*time.Millisecond))
 	})
+
+	It("returns error for nil function", func() {
+		br := run.NewBackgroundRunner(ctx)
+		Expect(br.Run(nil)).NotTo(BeNil())
+	})
+
+	It("waits for background runs", func() {
+		br := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{})
+		release := make(chan struct{})
+		Expect(br.Run(func(ctx context.Context) error {
+			<-release
+			return nil
+		})).To(BeNil())
+		Expect(br.Active()).To(Equal(1))
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer waitCancel()
+		Expect(br.Wait(waitCtx)).To(Equal(context.DeadlineExceeded))
+
+		close(release)
+		Expect(br.Wait(ctx)).To(BeNil())
+		Expect(br.Active()).To(Equal(0))
+	})
+
+	It("skips calls while running", func() {
+		br := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{})
+		release := make(chan struct{})
+		var counter int64
+		fn := func(ctx context.Context) error {
+			atomic.AddInt64(&counter, 1)
+			<-release
+			return nil
+		}
+		Expect(br.Run(fn)).To(BeNil())
+		Expect(br.Run(fn)).To(BeNil())
+		Expect(br.Active()).To(Equal(1))
+		close(release)
+		Expect(br.Wait(ctx)).To(BeNil())
+		Expect(atomic.LoadInt64(&counter)).To(Equal(int64(1)))
+	})
+
+	It("delivers errors to the error handler", func() {
+		errs := make(chan error, 1)
+		br := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{
+			ErrorHandler: func(ctx context.Context, err error) {
+				errs <- err
+			},
+		})
+		Expect(br.Run(func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})).To(BeNil())
+		Expect(br.Wait(ctx)).To(BeNil())
+		Expect(errs).To(Receive(MatchError("banana")))
+	})
+
+	Context("queue mode", func() {
+		It("runs all functions one after another", func() {
+			br := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{
+				Mode: run.BackgroundRunnerModeQueue,
+			})
+			release := make(chan struct{})
+			var mux sync.Mutex
+			var order []int
+			for i := 0; i < 3; i++ {
+				i := i
+				Expect(br.Run(func(ctx context.Context) error {
+					<-release
+					mux.Lock()
+					defer mux.Unlock()
+					order = append(order, i)
+					return nil
+				})).To(BeNil())
+			}
+			Expect(br.Active()).To(Equal(3))
+			close(release)
+			Expect(br.Wait(ctx)).To(BeNil())
+			Expect(order).To(Equal([]int{0, 1, 2}))
+		})
+		It("drops queued functions on cancel", func() {
+			br := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{
+				Mode: run.BackgroundRunnerModeQueue,
+			})
+			var counter int64
+			fn := func(ctx context.Context) error {
+				atomic.AddInt64(&counter, 1)
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			Expect(br.Run(fn)).To(BeNil())
+			Expect(br.Run(fn)).To(BeNil())
+			Expect(br.Run(fn)).To(BeNil())
+			cancel()
+			Expect(br.Wait(context.Background())).To(BeNil())
+			Expect(atomic.LoadInt64(&counter)).To(Equal(int64(1)))
+		})
+	})
+
+	Context("parallel mode", func() {
+		It("limits parallel runs", func() {
+			br := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{
+				Mode:        run.BackgroundRunnerModeParallel,
+				MaxParallel: 2,
+			})
+			release := make(chan struct{})
+			var running int64
+			var maxRunning int64
+			for i := 0; i < 5; i++ {
+				Expect(br.Run(func(ctx context.Context) error {
+					current := atomic.AddInt64(&running, 1)
+					for {
+						max := atomic.LoadInt64(&maxRunning)
+						if current <= max || atomic.CompareAndSwapInt64(&maxRunning, max, current) {
+							break
+						}
+					}
+					<-release
+					atomic.AddInt64(&running, -1)
+					return nil
+				})).To(BeNil())
+			}
+			Expect(br.Active()).To(Equal(5))
+			Eventually(func() int64 { return atomic.LoadInt64(&running) }).Should(Equal(int64(2)))
+			close(release)
+			Expect(br.Wait(ctx)).To(BeNil())
+			Expect(atomic.LoadInt64(&maxRunning)).To(Equal(int64(2)))
+		})
+	})
 })
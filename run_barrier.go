@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// Barrier is a cyclic barrier for a fixed number of parties.
+// All parties calling Await are released together once the last party arrived,
+// afterwards the barrier is reset for the next cycle. The Done channel is closed once the current cycle completed.
+type Barrier interface {
+	Done
+	// Await waits until all parties arrived or the context is canceled.
+	// A canceled party leaves the barrier, so the cycle needs another party to complete.
+	Await(ctx context.Context) error
+	// Parties returns the number of parties required to complete a cycle.
+	Parties() int
+	// Waiting returns the number of parties waiting in the current cycle.
+	Waiting() int
+}
+
+// NewBarrier creates a new Barrier for the given number of parties. A value less than one is treated as one.
+func NewBarrier(parties int) Barrier {
+	return &barrier{
+		parties: max(parties, 1),
+		cycle:   make(chan struct{}),
+	}
+}
+
+type barrier struct {
+	parties int
+
+	mux     sync.Mutex
+	waiting int
+	cycle   chan struct{}
+}
+
+func (b *barrier) Await(ctx context.Context) error {
+	b.mux.Lock()
+	cycle := b.cycle
+	b.waiting++
+	if b.waiting == b.parties {
+		b.waiting = 0
+		b.cycle = make(chan struct{})
+		close(cycle)
+		b.mux.Unlock()
+		return nil
+	}
+	b.mux.Unlock()
+
+	select {
+	case <-cycle:
+		return nil
+	case <-ctx.Done():
+		b.mux.Lock()
+		defer b.mux.Unlock()
+		if b.cycle != cycle {
+			// the cycle completed concurrently
+			return nil
+		}
+		b.waiting--
+		return ctx.Err()
+	}
+}
+
+func (b *barrier) Parties() int {
+	return b.parties
+}
+
+func (b *barrier) Waiting() int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.waiting
+}
+
+func (b *barrier) Done() <-chan struct{} {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.cycle
+}
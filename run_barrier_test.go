@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("Barrier", func() {
+	var ctx context.Context
+	var barrier run.Barrier
+	var await func(ctx context.Context) <-chan error
+	BeforeEach(func() {
+		ctx = context.Background()
+		barrier = run.NewBarrier(3)
+		await = func(ctx context.Context) <-chan error {
+			result := make(chan error, 1)
+			go func() {
+				result <- barrier.Await(ctx)
+			}()
+			return result
+		}
+	})
+	It("releases all parties once the last arrived", func() {
+		first := await(ctx)
+		second := await(ctx)
+		Eventually(barrier.Waiting).Should(Equal(2))
+		done := barrier.Done()
+		Consistently(first, 50*time.Millisecond).ShouldNot(Receive())
+		Expect(done).NotTo(BeClosed())
+
+		Expect(barrier.Await(ctx)).To(Succeed())
+		Eventually(first).Should(Receive(BeNil()))
+		Eventually(second).Should(Receive(BeNil()))
+		Expect(done).To(BeClosed())
+		Expect(barrier.Waiting()).To(Equal(0))
+		Expect(barrier.Parties()).To(Equal(3))
+	})
+	It("is reusable for the next cycle", func() {
+		for i := 0; i < 2; i++ {
+			first := await(ctx)
+			second := await(ctx)
+			Eventually(barrier.Waiting).Should(Equal(2))
+			Expect(barrier.Await(ctx)).To(Succeed())
+			Eventually(first).Should(Receive(BeNil()))
+			Eventually(second).Should(Receive(BeNil()))
+		}
+		Expect(barrier.Done()).NotTo(BeClosed())
+	})
+	It("removes a canceled party", func() {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		canceled := await(canceledCtx)
+		first := await(ctx)
+		Eventually(barrier.Waiting).Should(Equal(2))
+
+		cancel()
+		Eventually(canceled).Should(Receive(MatchError(context.Canceled)))
+		Expect(barrier.Waiting()).To(Equal(1))
+
+		second := await(ctx)
+		Eventually(barrier.Waiting).Should(Equal(2))
+		Expect(barrier.Await(ctx)).To(Succeed())
+		Eventually(first).Should(Receive(BeNil()))
+		Eventually(second).Should(Receive(BeNil()))
+	})
+})
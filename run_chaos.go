@@ -0,0 +1,198 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// ErrChaos is the default error injected by Chaos.
+var ErrChaos = stderrors.New("chaos")
+
+// ChaosPosition defines when Chaos injects faults relative to the wrapped function.
+type ChaosPosition int
+
+const (
+	// ChaosBefore injects the faults before the wrapped function is called.
+	ChaosBefore ChaosPosition = iota
+	// ChaosAfter injects the faults after the wrapped function returned.
+	ChaosAfter
+	// ChaosBeforeOrAfter randomly injects the faults before or after the wrapped function.
+	ChaosBeforeOrAfter
+)
+
+// ChaosConfig configures the faults injected by Chaos.
+// Each probability is between 0 (never) and 1 (always) and is evaluated independently on every call.
+type ChaosConfig struct {
+	// ErrorProbability is the probability to return Error.
+	ErrorProbability float64 `json:"errorProbability"`
+	// Error is the injected error. If nil, ErrChaos is used.
+	Error error `json:"-"`
+	// LatencyProbability is the probability to add a random latency up to MaxLatency.
+	// The latency ends early if the context is canceled.
+	LatencyProbability float64 `json:"latencyProbability"`
+	// MaxLatency is the maximum added latency.
+	MaxLatency time.Duration `json:"maxLatency"`
+	// PanicProbability is the probability to panic with ErrChaos.
+	PanicProbability float64 `json:"panicProbability"`
+	// HangProbability is the probability to hang for HangDuration, ignoring the context.
+	HangProbability float64 `json:"hangProbability"`
+	// HangDuration is the duration of a hang. Zero means the hang never ends.
+	HangDuration time.Duration `json:"hangDuration"`
+	// Position defines when the faults are injected.
+	Position ChaosPosition `json:"position"`
+	// Seed of the random source, which makes the injected faults reproducible.
+	// Zero means a random seed.
+	Seed uint64 `json:"seed"`
+	// Toggle enables and disables the fault injection at runtime. If nil, the injection is always enabled.
+	Toggle *ChaosToggle `json:"-"`
+	// Clock provides the timers of latencies and hangs. If nil, the DefaultClock is used.
+	Clock Clock `json:"-"`
+	// Logger is used to log the injected faults as debug messages. If nil, the DefaultLogger is used.
+	Logger Logger `json:"-"`
+}
+
+// ChaosToggle enables and disables the fault injection of Chaos at runtime.
+// It is safe for concurrent use.
+type ChaosToggle struct {
+	enabled atomic.Bool
+}
+
+// NewChaosToggle creates a new ChaosToggle with the given initial state.
+func NewChaosToggle(enabled bool) *ChaosToggle {
+	t := &ChaosToggle{}
+	t.enabled.Store(enabled)
+	return t
+}
+
+// Enable enables the fault injection.
+func (t *ChaosToggle) Enable() {
+	t.enabled.Store(true)
+}
+
+// Disable disables the fault injection, the wrapped function is called unchanged.
+func (t *ChaosToggle) Disable() {
+	t.enabled.Store(false)
+}
+
+// Enabled returns true if the fault injection is enabled.
+func (t *ChaosToggle) Enabled() bool {
+	return t.enabled.Load()
+}
+
+// Chaos wraps the given function and injects errors, latencies, panics and hangs according to the config.
+// It is meant to verify retries and circuit breakers in tests and staging, combine it with
+// a ChaosToggle to enable the injection only where wanted.
+func Chaos(fn Func, config ChaosConfig) Func {
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	c := &chaos{
+		config: config,
+		clock:  orDefaultClock(config.Clock),
+		logger: orDefaultLogger(config.Logger),
+		name:   funcName(fn),
+		rand:   rand.New(rand.NewPCG(seed, seed)),
+	}
+	return func(ctx context.Context) error {
+		if config.Toggle != nil && !config.Toggle.Enabled() {
+			return fn(ctx)
+		}
+		faults := c.roll()
+		if !faults.after {
+			if err := c.inject(ctx, faults); err != nil {
+				return err
+			}
+			return fn(ctx)
+		}
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return c.inject(ctx, faults)
+	}
+}
+
+type chaosFaults struct {
+	after   bool
+	latency time.Duration
+	hang    bool
+	panic   bool
+	err     bool
+}
+
+type chaos struct {
+	config ChaosConfig
+	clock  Clock
+	logger Logger
+	name   string
+
+	mux  sync.Mutex
+	rand *rand.Rand
+}
+
+// roll draws the faults of a single call.
+func (c *chaos) roll() chaosFaults {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	var faults chaosFaults
+	switch c.config.Position {
+	case ChaosAfter:
+		faults.after = true
+	case ChaosBeforeOrAfter:
+		faults.after = c.rand.Float64() < 0.5
+	}
+	if c.rand.Float64() < c.config.LatencyProbability && c.config.MaxLatency > 0 {
+		faults.latency = time.Duration(c.rand.Int64N(int64(c.config.MaxLatency))) + 1
+	}
+	faults.hang = c.rand.Float64() < c.config.HangProbability
+	faults.panic = c.rand.Float64() < c.config.PanicProbability
+	faults.err = c.rand.Float64() < c.config.ErrorProbability
+	return faults
+}
+
+func (c *chaos) inject(ctx context.Context, faults chaosFaults) error {
+	if faults.latency > 0 {
+		c.logger.Debug(ctx, "chaos => add latency", "func", c.name, "latency", faults.latency)
+		timer := c.clock.NewTimer(faults.latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C():
+		}
+	}
+	if faults.hang {
+		c.logger.Debug(ctx, "chaos => hang", "func", c.name)
+		c.hang()
+	}
+	if faults.panic {
+		c.logger.Debug(ctx, "chaos => panic", "func", c.name)
+		panic(ErrChaos)
+	}
+	if faults.err {
+		c.logger.Debug(ctx, "chaos => inject error", "func", c.name)
+		if c.config.Error != nil {
+			return c.config.Error
+		}
+		return errors.Wrap(ctx, ErrChaos, "inject error")
+	}
+	return nil
+}
+
+// hang blocks for the configured duration, ignoring the context.
+func (c *chaos) hang() {
+	if c.config.HangDuration <= 0 {
+		select {}
+	}
+	<-c.clock.NewTimer(c.config.HangDuration).C()
+}
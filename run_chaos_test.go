@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("Chaos", func() {
+	var ctx context.Context
+	var counter int
+	var fn run.Func
+	var config run.ChaosConfig
+	BeforeEach(func() {
+		ctx = context.Background()
+		counter = 0
+		fn = func(ctx context.Context) error {
+			counter++
+			return nil
+		}
+		config = run.ChaosConfig{}
+	})
+	It("calls the function unchanged without probabilities", func() {
+		Expect(run.Chaos(fn, config)(ctx)).To(BeNil())
+		Expect(counter).To(Equal(1))
+	})
+	It("injects the error before the function", func() {
+		config.ErrorProbability = 1
+		err := run.Chaos(fn, config)(ctx)
+		Expect(stderrors.Is(err, run.ErrChaos)).To(BeTrue())
+		Expect(counter).To(Equal(0))
+	})
+	It("injects the configured error after the function", func() {
+		banana := stderrors.New("banana")
+		config.ErrorProbability = 1
+		config.Error = banana
+		config.Position = run.ChaosAfter
+		Expect(run.Chaos(fn, config)(ctx)).To(Equal(banana))
+		Expect(counter).To(Equal(1))
+	})
+	It("logs the injected faults", func() {
+		buf := &syncBuffer{}
+		config.ErrorProbability = 1
+		config.Logger = run.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		})))
+		Expect(run.Chaos(fn, config)(ctx)).NotTo(BeNil())
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "chaos => inject error"))
+	})
+	It("panics", func() {
+		config.PanicProbability = 1
+		err := run.CatchPanic(run.Chaos(fn, config))(ctx)
+		var panicErr *run.PanicError
+		Expect(stderrors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal(run.ErrChaos))
+		Expect(counter).To(Equal(0))
+	})
+	It("does nothing if the toggle is disabled", func() {
+		config.ErrorProbability = 1
+		config.Toggle = run.NewChaosToggle(false)
+		chaos := run.Chaos(fn, config)
+		Expect(chaos(ctx)).To(BeNil())
+
+		config.Toggle.Enable()
+		Expect(chaos(ctx)).NotTo(BeNil())
+		Expect(config.Toggle.Enabled()).To(BeTrue())
+
+		config.Toggle.Disable()
+		Expect(chaos(ctx)).To(BeNil())
+		Expect(counter).To(Equal(2))
+	})
+	It("injects the same faults with the same seed", func() {
+		config.ErrorProbability = 0.5
+		config.Seed = 42
+		results := func() []bool {
+			chaos := run.Chaos(fn, config)
+			var result []bool
+			for i := 0; i < 20; i++ {
+				result = append(result, chaos(ctx) != nil)
+			}
+			return result
+		}
+		first := results()
+		Expect(first).To(ContainElement(true))
+		Expect(first).To(ContainElement(false))
+		Expect(results()).To(Equal(first))
+	})
+	Context("with fake clock", func() {
+		var clock run.FakeClock
+		var errs chan error
+		BeforeEach(func() {
+			clock = run.NewFakeClock(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+			config.Clock = clock
+			errs = make(chan error, 1)
+		})
+		It("adds latency", func() {
+			config.LatencyProbability = 1
+			config.MaxLatency = time.Minute
+			chaos := run.Chaos(fn, config)
+			go func() { errs <- chaos(ctx) }()
+			Eventually(clock.PendingTimers).Should(Equal(1))
+			Consistently(errs, 50*time.Millisecond).ShouldNot(Receive())
+
+			clock.Advance(time.Minute)
+			Eventually(errs).Should(Receive(BeNil()))
+			Expect(counter).To(Equal(1))
+		})
+		It("ends the latency if the context is canceled", func() {
+			config.LatencyProbability = 1
+			config.MaxLatency = time.Minute
+			ctx, cancel := context.WithCancel(ctx)
+			chaos := run.Chaos(fn, config)
+			go func() { errs <- chaos(ctx) }()
+			Eventually(clock.PendingTimers).Should(Equal(1))
+
+			cancel()
+			Eventually(errs).Should(Receive(MatchError(context.Canceled)))
+			Expect(counter).To(Equal(0))
+		})
+		It("hangs ignoring the context", func() {
+			config.HangProbability = 1
+			config.HangDuration = time.Minute
+			ctx, cancel := context.WithCancel(ctx)
+			chaos := run.Chaos(fn, config)
+			go func() { errs <- chaos(ctx) }()
+			Eventually(clock.PendingTimers).Should(Equal(1))
+
+			cancel()
+			Consistently(errs, 50*time.Millisecond).ShouldNot(Receive())
+
+			clock.Advance(time.Minute)
+			Eventually(errs).Should(Receive(BeNil()))
+		})
+	})
+})
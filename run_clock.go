@@ -0,0 +1,206 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers. It allows tests to control time with a FakeClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that sends the current time on its channel after at least d.
+	NewTimer(d time.Duration) Timer
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a single event timer created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. It returns false if the timer already fired or was stopped.
+	Stop() bool
+	// Reset changes the timer to expire after duration d. It returns true if the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// DefaultClock is used by all functions that have no clock injected.
+var DefaultClock Clock = NewRealClock()
+
+// orDefaultClock returns the given clock or the DefaultClock if it is nil.
+func orDefaultClock(clock Clock) Clock {
+	if clock == nil {
+		return DefaultClock
+	}
+	return clock
+}
+
+// NewRealClock creates a Clock backed by the time package.
+func NewRealClock() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (r realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (r realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{timer: time.NewTimer(d)}
+}
+
+func (r realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (r *realTimer) C() <-chan time.Time {
+	return r.timer.C
+}
+
+func (r *realTimer) Stop() bool {
+	return r.timer.Stop()
+}
+
+func (r *realTimer) Reset(d time.Duration) bool {
+	return r.timer.Reset(d)
+}
+
+// FakeClock is a manually controlled Clock for tests. Time only moves with Advance
+// and timers fire once the time reached their deadline.
+type FakeClock interface {
+	Clock
+	// Advance moves the time forward by d and fires all timers that expired.
+	Advance(d time.Duration)
+	// PendingTimers returns the number of timers that have not fired or been stopped.
+	PendingTimers() int
+}
+
+// NewFakeClock creates a FakeClock starting at the given time.
+func NewFakeClock(now time.Time) FakeClock {
+	return &fakeClock{
+		now: now,
+	}
+}
+
+type fakeClock struct {
+	mux    sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) NewTimer(d time.Duration) Timer {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	timer := &fakeTimer{
+		clock: f,
+		c:     make(chan time.Time, 1),
+	}
+	f.schedule(timer, d)
+	return timer
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.now = f.now.Add(d)
+	sort.SliceStable(f.timers, func(i, j int) bool {
+		return f.timers[i].deadline.Before(f.timers[j].deadline)
+	})
+	var pending []*fakeTimer
+	for _, timer := range f.timers {
+		if timer.deadline.After(f.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		select {
+		case timer.c <- f.now:
+		default:
+		}
+	}
+	f.timers = pending
+}
+
+func (f *fakeClock) PendingTimers() int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return len(f.timers)
+}
+
+// schedule adds the timer with a deadline of d from now, the caller must hold the lock.
+func (f *fakeClock) schedule(timer *fakeTimer, d time.Duration) {
+	timer.deadline = f.now.Add(d)
+	if d <= 0 {
+		select {
+		case timer.c <- f.now:
+		default:
+		}
+		return
+	}
+	f.timers = append(f.timers, timer)
+}
+
+// unschedule removes the timer and returns true if it was pending, the caller must hold the lock.
+func (f *fakeClock) unschedule(timer *fakeTimer) bool {
+	for i, t := range f.timers {
+		if t == timer {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (f *fakeTimer) C() <-chan time.Time {
+	return f.c
+}
+
+func (f *fakeTimer) Stop() bool {
+	f.clock.mux.Lock()
+	defer f.clock.mux.Unlock()
+	f.drain()
+	return f.clock.unschedule(f)
+}
+
+func (f *fakeTimer) Reset(d time.Duration) bool {
+	f.clock.mux.Lock()
+	defer f.clock.mux.Unlock()
+	f.drain()
+	active := f.clock.unschedule(f)
+	f.clock.schedule(f, d)
+	return active
+}
+
+// drain discards a fired but not received time like timers of the time package since Go 1.23.
+func (f *fakeTimer) drain() {
+	select {
+	case <-f.c:
+	default:
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("Clock", func() {
+	var ctx context.Context
+	var start time.Time
+	var clock run.FakeClock
+	BeforeEach(func() {
+		ctx = context.Background()
+		start = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		clock = run.NewFakeClock(start)
+	})
+	It("returns the real time", func() {
+		Expect(run.NewRealClock().Now()).To(BeTemporally("~", time.Now(), time.Second))
+	})
+	It("only moves with Advance", func() {
+		Expect(clock.Now()).To(Equal(start))
+		clock.Advance(time.Minute)
+		Expect(clock.Now()).To(Equal(start.Add(time.Minute)))
+	})
+	It("fires timers when their deadline is reached", func() {
+		timer := clock.NewTimer(time.Minute)
+		after := clock.After(2 * time.Minute)
+		Expect(clock.PendingTimers()).To(Equal(2))
+
+		clock.Advance(59 * time.Second)
+		Expect(timer.C()).NotTo(Receive())
+
+		clock.Advance(time.Second)
+		Expect(timer.C()).To(Receive(Equal(start.Add(time.Minute))))
+		Expect(clock.PendingTimers()).To(Equal(1))
+
+		clock.Advance(time.Hour)
+		Expect(after).To(Receive())
+		Expect(clock.PendingTimers()).To(Equal(0))
+	})
+	It("stops and resets timers", func() {
+		timer := clock.NewTimer(time.Minute)
+		Expect(timer.Stop()).To(BeTrue())
+		Expect(timer.Stop()).To(BeFalse())
+		Expect(clock.PendingTimers()).To(Equal(0))
+
+		Expect(timer.Reset(time.Second)).To(BeFalse())
+		Expect(clock.PendingTimers()).To(Equal(1))
+		clock.Advance(time.Second)
+		Expect(timer.C()).To(Receive())
+	})
+	It("fires timers without duration immediately", func() {
+		Expect(clock.After(0)).To(Receive())
+		Expect(clock.PendingTimers()).To(Equal(0))
+	})
+	It("controls the waiter", func() {
+		waiter := run.NewWaiterWithClock(clock)
+		done := make(chan error, 1)
+		go func() {
+			done <- waiter.Wait(ctx, time.Hour)
+		}()
+		Eventually(clock.PendingTimers).Should(Equal(1))
+		Consistently(done).ShouldNot(Receive())
+		clock.Advance(time.Hour)
+		Eventually(done).Should(Receive(BeNil()))
+	})
+	It("controls retries through the waiter", func() {
+		var counter int
+		fn := run.RetryWaiter(
+			run.Backoff{Delay: time.Minute, Retries: 1},
+			run.NewWaiterWithClock(clock),
+			func(ctx context.Context) error {
+				counter++
+				if counter == 1 {
+					return stderrors.New("banana")
+				}
+				return nil
+			},
+		)
+		done := make(chan error, 1)
+		go func() {
+			done <- fn(ctx)
+		}()
+		Eventually(clock.PendingTimers).Should(Equal(1))
+		clock.Advance(time.Minute)
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(counter).To(Equal(2))
+	})
+	It("controls delayed functions", func() {
+		called := make(chan struct{}, 1)
+		fn := run.DelayedWithClock(func(ctx context.Context) error {
+			called <- struct{}{}
+			return nil
+		}, time.Minute, clock)
+		done := make(chan error, 1)
+		go func() {
+			done <- fn(ctx)
+		}()
+		Eventually(clock.PendingTimers).Should(Equal(1))
+		Expect(called).NotTo(Receive())
+		clock.Advance(time.Minute)
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(called).To(Receive())
+	})
+	It("controls the metrics", func() {
+		registry := prometheus.NewRegistry()
+		fn := run.NewMetricsWithOptions(registry, run.MetricsOptions{
+			Namespace: "ns",
+			Subsystem: "sub",
+			Clock:     clock,
+		}, func(ctx context.Context) error {
+			clock.Advance(3 * time.Second)
+			return nil
+		})
+		Expect(fn(ctx)).To(Succeed())
+		Expect(metricValue(registry, "ns_sub_last_success", nil)).To(
+			Equal(float64(start.Add(3 * time.Second).Unix())),
+		)
+	})
+	It("controls the metrics of the factory", func() {
+		registry := prometheus.NewRegistry()
+		factory := run.NewMetricsFactory(registry, run.MetricsOptions{
+			Namespace: "ns",
+			Subsystem: "sub",
+			Clock:     clock,
+		})
+		fn := factory.Metrics("job", func(ctx context.Context) error {
+			clock.Advance(3 * time.Second)
+			return nil
+		})
+		Expect(fn(ctx)).To(Succeed())
+		Expect(metricValue(registry, "ns_sub_last_success", map[string]string{"job": "job"})).To(
+			Equal(float64(start.Add(3 * time.Second).Unix())),
+		)
+	})
+	It("controls the health registry", func() {
+		healthRegistry := run.NewHealthRegistryWithClock(clock)
+		fn := healthRegistry.Track("job", time.Minute, func(ctx context.Context) error {
+			return nil
+		})
+		Expect(fn(ctx)).To(Succeed())
+		Expect(healthRegistry.Status().Ready).To(BeTrue())
+		clock.Advance(2 * time.Minute)
+		Expect(healthRegistry.Status().Ready).To(BeFalse())
+	})
+	It("controls the watchdog", func() {
+		reporter := &recordingWatchdogReporter{}
+		watchdog := run.NewWatchdogWithClock(clock, time.Minute, reporter)
+		watchdog.Watch("job", time.Hour, func(ctx context.Context) error {
+			return nil
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			_ = watchdog.Run(ctx)
+		}()
+		Eventually(clock.PendingTimers).Should(Equal(1))
+		clock.Advance(time.Minute)
+		Eventually(clock.PendingTimers).Should(Equal(1))
+		Expect(reporter.Reports()).To(BeEmpty())
+		clock.Advance(time.Hour)
+		Eventually(reporter.Reports).Should(HaveLen(1))
+	})
+	It("controls the error log throttle window", func() {
+		buf := &syncBuffer{}
+		fn := run.LogErrorsThrottled(run.ErrorLogThrottleOptions{
+			Window: 5 * time.Minute,
+			Logger: run.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, nil))),
+			Clock:  clock,
+		}, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).NotTo(Succeed())
+		Expect(fn(ctx)).NotTo(Succeed())
+		clock.Advance(5 * time.Minute)
+		Eventually(buf.Records).Should(HaveLen(2))
+		Expect(buf.Records()[1]).To(HaveKeyWithValue("msg", "run failed: repeated 1 times in 5m0s"))
+	})
+	It("controls the duration logged by the error log throttle", func() {
+		buf := &syncBuffer{}
+		fn := run.LogErrorsThrottled(run.ErrorLogThrottleOptions{
+			Window: 5 * time.Minute,
+			Logger: run.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, nil))),
+			Clock:  clock,
+		}, func(ctx context.Context) error {
+			clock.Advance(3 * time.Second)
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).NotTo(Succeed())
+		Expect(buf.Records()).To(HaveLen(1))
+		Expect(buf.Records()[0]).To(HaveKeyWithValue("duration", float64(3*time.Second)))
+	})
+	It("controls the shutdown hook timeout", func() {
+		manager := run.NewShutdownManager(run.ShutdownOptions{
+			Signals: []os.Signal{syscall.SIGUSR2},
+			Exit:    func(code int) {},
+			Clock:   clock,
+		})
+		hookCtx := make(chan context.Context, 1)
+		manager.Register("hang", time.Minute, func(ctx context.Context) error {
+			hookCtx <- ctx
+			<-ctx.Done()
+			return nil
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- manager.Run(ctx)
+		}()
+		var hook context.Context
+		Eventually(hookCtx).Should(Receive(&hook))
+		Consistently(errs, 50*time.Millisecond).ShouldNot(Receive())
+		clock.Advance(time.Minute)
+		var err error
+		Eventually(errs).Should(Receive(&err))
+		Expect(stderrors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(context.Cause(hook)).To(Equal(context.DeadlineExceeded))
+	})
+})
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConcurrentRunnerMetrics records the behavior of a ConcurrentRunner.
+type ConcurrentRunnerMetrics interface {
+	// Queued is called before a function is added.
+	Queued()
+	// Started is called when a queued function starts running.
+	Started()
+	// Finished is called when a running function completed.
+	Finished()
+	// Rejected is called instead of Started when a queued function could not be added
+	// because the runner is closed or the context is canceled, or was still queued when Run returned.
+	Rejected()
+}
+
+// NewConcurrentRunnerMetrics creates ConcurrentRunnerMetrics backed by Prometheus collectors registered with the given registerer.
+// Already registered equal collectors are reused.
+func NewConcurrentRunnerMetrics(
+	registerer prometheus.Registerer,
+	namespace string,
+	subsystem string,
+) ConcurrentRunnerMetrics {
+	return &concurrentRunnerMetrics{
+		queued: registerOrExisting(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "concurrent_runner_queued",
+			Help:      "Number of functions waiting to run",
+		})),
+		running: registerOrExisting(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "concurrent_runner_running",
+			Help:      "Number of functions currently running",
+		})),
+		rejected: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "concurrent_runner_rejected_total",
+			Help:      "Total number of rejected functions",
+		})),
+	}
+}
+
+type concurrentRunnerMetrics struct {
+	queued   prometheus.Gauge
+	running  prometheus.Gauge
+	rejected prometheus.Counter
+}
+
+func (c *concurrentRunnerMetrics) Queued() {
+	c.queued.Inc()
+}
+
+func (c *concurrentRunnerMetrics) Started() {
+	c.queued.Dec()
+	c.running.Inc()
+}
+
+func (c *concurrentRunnerMetrics) Finished() {
+	c.running.Dec()
+}
+
+func (c *concurrentRunnerMetrics) Rejected() {
+	c.queued.Dec()
+	c.rejected.Inc()
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("ConcurrentRunnerMetrics", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var registry *prometheus.Registry
+	var runner run.ConcurrentRunner
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		registry = prometheus.NewRegistry()
+		runner = run.NewConcurrentRunnerWithMetrics(
+			1,
+			run.NewConcurrentRunnerMetrics(registry, "ns", "sub"),
+		)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("records queued and running functions", func() {
+		release := make(chan struct{})
+		started := make(chan struct{}, 2)
+		fn := func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}
+		runner.Add(ctx, fn)
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_queued", nil)).To(Equal(1.0))
+
+		done := make(chan error, 1)
+		go func() {
+			done <- runner.Run(ctx)
+		}()
+		Eventually(started).Should(Receive())
+		runner.Add(ctx, fn)
+		Eventually(func() float64 {
+			return metricValue(registry, "ns_sub_concurrent_runner_queued", nil)
+		}).Should(Equal(1.0))
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_running", nil)).To(Equal(1.0))
+
+		close(release)
+		Eventually(started).Should(Receive())
+		Expect(runner.Close()).To(BeNil())
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_queued", nil)).To(Equal(0.0))
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_running", nil)).To(Equal(0.0))
+	})
+	It("records rejected functions", func() {
+		Expect(runner.Close()).To(BeNil())
+		runner.Add(ctx, func(ctx context.Context) error { return nil })
+		Expect(
+			metricValue(registry, "ns_sub_concurrent_runner_rejected_total", nil),
+		).To(Equal(1.0))
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_queued", nil)).To(Equal(0.0))
+	})
+	It("records functions rejected by a canceled context", func() {
+		fn := func(ctx context.Context) error { return nil }
+		runner.Add(ctx, fn)
+		cancel()
+		runner.Add(ctx, fn)
+		Expect(
+			metricValue(registry, "ns_sub_concurrent_runner_rejected_total", nil),
+		).To(Equal(1.0))
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_queued", nil)).To(Equal(1.0))
+	})
+	It("rejects functions still queued when Run returns", func() {
+		cancel()
+		fn := func(ctx context.Context) error { return nil }
+		for i := 0; i < 10; i++ {
+			runner = run.NewConcurrentRunnerWithMetrics(
+				1,
+				run.NewConcurrentRunnerMetrics(registry, "ns", "sub"),
+			)
+			runner.Add(context.Background(), fn)
+			Expect(runner.Run(ctx)).NotTo(BeNil())
+			Expect(metricValue(registry, "ns_sub_concurrent_runner_queued", nil)).To(Equal(0.0))
+		}
+		Expect(metricValue(registry, "ns_sub_concurrent_runner_running", nil)).To(Equal(0.0))
+	})
+})
@@ -11,7 +11,6 @@ import (
 	"sync"
 
 	"github.com/bborbe/errors"
-	"github.com/golang/glog"
 )
 
 // ConcurrentRunner manages concurrent execution of functions with a configurable concurrency limit.
@@ -25,30 +24,63 @@ type ConcurrentRunner interface {
 // NewConcurrentRunner creates a new ConcurrentRunner that limits concurrent execution to maxConcurrent functions.
 // The runner must be closed when no longer needed to clean up resources.
 func NewConcurrentRunner(maxConcurrent int) ConcurrentRunner {
+	return NewConcurrentRunnerWithMetrics(maxConcurrent, nil)
+}
+
+// NewConcurrentRunnerWithMetrics creates a new ConcurrentRunner like NewConcurrentRunner
+// that records queued, running and rejected functions in the given metrics.
+func NewConcurrentRunnerWithMetrics(
+	maxConcurrent int,
+	metrics ConcurrentRunnerMetrics,
+) ConcurrentRunner {
+	return NewConcurrentRunnerWithOptions(maxConcurrent, ConcurrentRunnerOptions{
+		Metrics: metrics,
+	})
+}
+
+// ConcurrentRunnerOptions configures a ConcurrentRunner.
+type ConcurrentRunnerOptions struct {
+	// Metrics is an optional recorder for queued, running and rejected functions.
+	Metrics ConcurrentRunnerMetrics
+	// Logger is used for logging. If nil, the DefaultLogger is used.
+	Logger Logger
+}
+
+// NewConcurrentRunnerWithOptions creates a new ConcurrentRunner like NewConcurrentRunner
+// configured by the given options.
+func NewConcurrentRunnerWithOptions(
+	maxConcurrent int,
+	options ConcurrentRunnerOptions,
+) ConcurrentRunner {
 	return &concurrentRunner{
 		maxConcurrent: maxConcurrent,
 		fns:           make(chan Func, maxConcurrent),
 		closed:        make(chan struct{}),
+		metrics:       options.Metrics,
+		logger:        orDefaultLogger(options.Logger),
 	}
 }
 
 type concurrentRunner struct {
 	fns           chan Func
 	maxConcurrent int
+	metrics       ConcurrentRunnerMetrics
+	logger        Logger
 
 	mux    sync.Mutex
 	closed chan struct{}
 }
 
 func (c *concurrentRunner) Close() error {
+	ctx := context.Background()
 	c.mux.Lock()
 	defer c.mux.Unlock()
 	select {
 	case <-c.closed:
-		glog.V(3).Infof("already closed => skip")
+		c.logger.Debug(ctx, "already closed => skip")
 		return stderrors.New("already closed")
 	default:
-		glog.V(3).Infof("close concurrent runner")
+		c.logger.Debug(ctx, "close concurrent runner")
 		close(c.closed)
 		close(c.fns)
 		return nil
@@ -58,31 +90,48 @@ func (c *concurrentRunner) Close() error {
 func (c *concurrentRunner) Add(ctx context.Context, fn Func) {
 	c.mux.Lock()
 	defer c.mux.Unlock()
+	// count as queued before the send, so Run can't record the start before the queuing
+	if c.metrics != nil {
+		c.metrics.Queued()
+	}
 	select {
 	case <-c.closed:
-		glog.V(3).Infof("close discard added fn")
+		c.logger.Debug(ctx, "close discard added fn", "func", funcName(fn))
+		c.rejected()
 	default:
 		select {
 		case <-ctx.Done():
+			c.rejected()
 		case c.fns <- fn:
-			glog.V(3).Infof("fn add to concurrent runner")
+			c.logger.Debug(ctx, "fn add to concurrent runner", "func", funcName(fn))
 		}
 	}
 }
 
+func (c *concurrentRunner) rejected() {
+	if c.metrics != nil {
+		c.metrics.Rejected()
+	}
+}
+
 func (c *concurrentRunner) Run(ctx context.Context) error {
 	var wg sync.WaitGroup
 	errs := make(chan error)
 	limit := make(chan struct{}, c.maxConcurrent)
+	// CancelOnFirstError returns without waiting for the loop, which may still start functions
+	loopDone := make(chan struct{})
 	defer func() {
+		<-loopDone
 		wg.Wait()
 		close(limit)
 		close(errs)
+		c.drain(ctx)
 	}()
 
 	return CancelOnFirstError(
 		ctx,
 		func(ctx context.Context) error {
+			defer close(loopDone)
 			for {
 				select {
 				case <-ctx.Done():
@@ -91,22 +140,33 @@ func (c *concurrentRunner) Run(ctx context.Context) error {
 					if !ok {
 						return nil
 					}
-					limit <- struct{}{}
+					select {
+					case <-ctx.Done():
+						c.rejected()
+						return ctx.Err()
+					case limit <- struct{}{}:
+					}
+					if c.metrics != nil {
+						c.metrics.Started()
+					}
 					wg.Add(1)
-					go func() {
+					goLabeled(ctx, "ConcurrentRunner", func() {
 						defer func() {
+							if c.metrics != nil {
+								c.metrics.Finished()
+							}
 							wg.Done()
-							glog.V(3).Infof("fn complete to concurrent runner")
+							c.logger.Debug(ctx, "fn complete to concurrent runner", "func", funcName(fn))
 							<-limit
 						}()
-						err := fn(ctx)
+						err := catchPanicIfEnabled(ctx, fn)(ctx)
 						if err != nil {
 							select {
 							case <-ctx.Done():
 							case errs <- errors.Wrap(ctx, err, "execute fn failed"):
 							}
 						}
-					}()
+					})
 				}
 			}
 		},
@@ -122,3 +182,19 @@ func (c *concurrentRunner) Run(ctx context.Context) error {
 		},
 	)
 }
+
+// drain rejects all functions that were queued but not started before Run returned.
+func (c *concurrentRunner) drain(ctx context.Context) {
+	for {
+		select {
+		case fn, ok := <-c.fns:
+			if !ok {
+				return
+			}
+			c.logger.Debug(ctx, "run finished => reject queued fn", "func", funcName(fn))
+			c.rejected()
+		default:
+			return
+		}
+	}
+}
@@ -6,33 +6,65 @@ package run
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/bborbe/errors"
 	"github.com/golang/glog"
 )
 
+// SignalError is the cause of a context canceled by ContextWithSignals.
+// It can be retrieved with context.Cause and errors.As.
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (s SignalError) Error() string {
+	return fmt.Sprintf("received signal %s", s.Signal)
+}
+
+// SignalFromContext returns the signal that canceled the given context, if any.
+func SignalFromContext(ctx context.Context) (os.Signal, bool) {
+	var signalError SignalError
+	if errors.As(context.Cause(ctx), &signalError) {
+		return signalError.Signal, true
+	}
+	return nil, false
+}
+
 // ContextWithSig creates a new context that is canceled when the process receives termination signals.
 // It listens for SIGINT and SIGTERM signals and cancels the returned context when any of these signals are received.
 // This is useful for graceful shutdown of long-running processes.
 func ContextWithSig(ctx context.Context) context.Context {
+	return ContextWithSignals(ctx, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+}
+
+// ContextWithSignals creates a new context that is canceled when the process receives one of the given signals.
+// If no signals are given, SIGINT and SIGTERM are used.
+// The received signal is available as SignalError via context.Cause or SignalFromContext.
+func ContextWithSignals(ctx context.Context, signals ...os.Signal) context.Context {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
 	// #nosec G118 -- the cancel func is not leaked: the goroutine below owns it
 	// and defers it, so it runs on signal receipt or on parent-ctx cancellation.
-	ctxWithCancel, cancel := context.WithCancel(ctx)
-	go func() {
-		defer cancel()
+	ctxWithCancel, cancel := context.WithCancelCause(ctx)
+	goLabeled(ctx, "ContextWithSignals", func() {
+		defer cancel(nil)
 
 		signalCh := make(chan os.Signal, 1)
-		signal.Notify(signalCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+		signal.Notify(signalCh, signals...)
 		defer signal.Stop(signalCh)
 
 		select {
 		case sig := <-signalCh:
 			glog.V(2).Infof("got signal %s => cancel context ", sig)
+			cancel(SignalError{Signal: sig})
 		case <-ctx.Done():
 		}
-	}()
+	})
 
 	return ctxWithCancel
 }
@@ -6,6 +6,9 @@ package run_test
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -225,3 +228,58 @@ var _ = Describe("ContextWithSig", func() {
 		})
 	})
 })
+
+var _ = Describe("ContextWithSignals", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var sink chan os.Signal
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		// keep a handler registered so the signal never terminates the test binary
+		sink = make(chan os.Signal, 10)
+		signal.Notify(sink, syscall.SIGUSR2)
+	})
+
+	AfterEach(func() {
+		cancel()
+		signal.Stop(sink)
+	})
+
+	It("cancels the context with the received signal as cause", func() {
+		sigCtx := run.ContextWithSignals(ctx, syscall.SIGUSR2)
+		process, err := os.FindProcess(os.Getpid())
+		Expect(err).To(BeNil())
+		Eventually(func() <-chan struct{} {
+			Expect(process.Signal(syscall.SIGUSR2)).To(BeNil())
+			return sigCtx.Done()
+		}).Should(BeClosed())
+
+		Expect(sigCtx.Err()).To(Equal(context.Canceled))
+		Expect(context.Cause(sigCtx)).To(Equal(run.SignalError{Signal: syscall.SIGUSR2}))
+		sig, ok := run.SignalFromContext(sigCtx)
+		Expect(ok).To(BeTrue())
+		Expect(sig).To(Equal(syscall.SIGUSR2))
+	})
+
+	Context("without signals", func() {
+		It("ignores signals other than SIGINT and SIGTERM", func() {
+			sigCtx := run.ContextWithSignals(ctx)
+			process, err := os.FindProcess(os.Getpid())
+			Expect(err).To(BeNil())
+			Expect(process.Signal(syscall.SIGUSR2)).To(BeNil())
+			Eventually(sink).Should(Receive(Equal(syscall.SIGUSR2)))
+			Consistently(sigCtx.Done(), 50*time.Millisecond).ShouldNot(BeClosed())
+		})
+	})
+
+	It("reports no signal if the parent context is canceled", func() {
+		sigCtx := run.ContextWithSignals(ctx, syscall.SIGUSR2)
+		cancel()
+		Eventually(sigCtx.Done()).Should(BeClosed())
+
+		Expect(context.Cause(sigCtx)).To(Equal(context.Canceled))
+		_, ok := run.SignalFromContext(sigCtx)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,202 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DebounceOptions configures how calls are collapsed into executions.
+type DebounceOptions struct {
+	// Wait is the quiet period without calls after which the collapsed execution happens.
+	Wait time.Duration `json:"wait"`
+	// MaxWait is the maximum time an execution may be delayed by a continuous stream of calls.
+	// Zero means no limit.
+	MaxWait time.Duration `json:"maxWait"`
+	// Leading executes the function on the first call of a burst.
+	Leading bool `json:"leading"`
+	// Trailing executes the function after the burst, if calls arrived after the last execution.
+	Trailing bool `json:"trailing"`
+	// Logger is used for logging. If nil, the DefaultLogger is used.
+	Logger Logger `json:"-"`
+}
+
+// Debounce wraps a function so that bursts of calls collapse into a single trailing execution.
+// The execution happens once no call arrived for wait, but at the latest after maxWait (zero means no limit).
+// To collapse bursts of Trigger.Fire, use DebounceFire.
+// It uses the DefaultWaiter for delays.
+func Debounce(wait time.Duration, maxWait time.Duration, fn Func) Func {
+	return DebounceWaiter(DebounceOptions{
+		Wait:     wait,
+		MaxWait:  maxWait,
+		Trailing: true,
+	}, DefaultWaiter, fn)
+}
+
+// DebounceWaiter wraps a function so that bursts of calls collapse into leading and/or trailing executions.
+// Calls return immediately; executions happen in a background goroutine, never overlap and use the
+// context of the most recent call. A pending execution is dropped if that context is canceled.
+// Failed executions are logged as warnings. The waiter controls how delays are implemented.
+func DebounceWaiter(options DebounceOptions, waiter Waiter, fn Func) Func {
+	d := &debouncer{
+		options: options,
+		waiter:  waiter,
+		fn:      fn,
+		name:    funcName(fn),
+	}
+	return d.Call
+}
+
+type debouncer struct {
+	options DebounceOptions
+	waiter  Waiter
+	fn      Func
+	name    string
+
+	mux     sync.Mutex
+	running bool
+	calls   uint64
+	pending bool
+	ctx     context.Context
+}
+
+func (d *debouncer) Call(ctx context.Context) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.calls++
+	d.pending = true
+	d.ctx = ctx
+	if d.running {
+		d.logger().Debug(ctx, "debounce => call collapsed", "func", d.name)
+		return nil
+	}
+	d.running = true
+	var leading context.Context
+	if d.options.Leading {
+		// the leading execution covers this call
+		d.pending = false
+		leading = ctx
+	}
+	goLabeled(ctx, "Debounce", func() { d.loop(leading) })
+	return nil
+}
+
+func (d *debouncer) loop(leading context.Context) {
+	if leading != nil {
+		d.execute(leading)
+	}
+	var waited time.Duration
+	for {
+		d.mux.Lock()
+		calls := d.calls
+		ctx := d.ctx
+		d.mux.Unlock()
+
+		wait := d.options.Wait
+		if d.options.MaxWait > 0 && d.options.MaxWait-waited < wait {
+			wait = d.options.MaxWait - waited
+		}
+		if err := d.waiter.Wait(ctx, wait); err != nil {
+			d.mux.Lock()
+			if d.calls != calls {
+				// a newer call brought a new context
+				d.mux.Unlock()
+				continue
+			}
+			d.logger().Debug(ctx, "debounce canceled => drop pending execution", "func", d.name)
+			d.stop()
+			d.mux.Unlock()
+			return
+		}
+		waited += wait
+
+		d.mux.Lock()
+		quiet := d.calls == calls
+		due := d.options.MaxWait > 0 && waited >= d.options.MaxWait
+		if !quiet && !due {
+			d.mux.Unlock()
+			continue
+		}
+		if !d.pending || !d.options.Trailing {
+			d.stop()
+			d.mux.Unlock()
+			return
+		}
+		d.pending = false
+		ctx = d.ctx
+		d.mux.Unlock()
+
+		d.execute(ctx)
+		waited = 0
+
+		d.mux.Lock()
+		// with leading executions the next window is a cooldown, otherwise a call right
+		// after the trailing execution would start a new burst and execute immediately
+		if quiet && !d.pending && !d.options.Leading {
+			d.stop()
+			d.mux.Unlock()
+			return
+		}
+		d.mux.Unlock()
+	}
+}
+
+// stop ends the current burst, the caller must hold the lock.
+func (d *debouncer) stop() {
+	d.pending = false
+	d.running = false
+	d.ctx = nil
+}
+
+func (d *debouncer) execute(ctx context.Context) {
+	d.logger().Debug(ctx, "debounce => execute", "func", d.name)
+	start := time.Now()
+	if err := catchPanicIfEnabled(ctx, d.fn)(ctx); err != nil {
+		d.logger().Warn(ctx, "run failed", runFailedArgs(d.name, time.Since(start), err)...)
+	}
+}
+
+func (d *debouncer) logger() Logger {
+	return orDefaultLogger(d.options.Logger)
+}
+
+// DebounceFire wraps a Fire so that bursts of Fire calls collapse into a single trailing Fire.
+// The Fire happens once no call arrived for wait, but at the latest after maxWait (zero means no limit).
+// Pending fires are dropped once the context is canceled.
+//
+//	trigger := run.NewTrigger()
+//	fire := run.DebounceFire(ctx, time.Second, 0, trigger)
+//	go watchChanges(fire)
+//	err := run.Triggered(reload, trigger.Done())(ctx)
+func DebounceFire(ctx context.Context, wait time.Duration, maxWait time.Duration, fire Fire) Fire {
+	return DebounceFireWaiter(ctx, DebounceOptions{
+		Wait:     wait,
+		MaxWait:  maxWait,
+		Trailing: true,
+	}, DefaultWaiter, fire)
+}
+
+// DebounceFireWaiter wraps a Fire like DebounceFire with the given options and waiter.
+func DebounceFireWaiter(ctx context.Context, options DebounceOptions, waiter Waiter, fire Fire) Fire {
+	fn := DebounceWaiter(options, waiter, func(ctx context.Context) error {
+		fire.Fire()
+		return nil
+	})
+	return &debouncedFire{
+		ctx: ctx,
+		fn:  fn,
+	}
+}
+
+type debouncedFire struct {
+	ctx context.Context
+	fn  Func
+}
+
+func (d *debouncedFire) Fire() {
+	_ = d.fn(d.ctx)
+}
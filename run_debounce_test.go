@@ -0,0 +1,332 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/mocks"
+)
+
+var _ = Describe("Debounce", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var waiter *mocks.Waiter
+	var waits chan chan error
+	var counter int64
+	var fn run.Func
+	var options run.DebounceOptions
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		counter = 0
+		waits = make(chan chan error)
+		waits := waits
+		waiter = &mocks.Waiter{}
+		waiter.WaitStub = func(ctx context.Context, wait time.Duration) error {
+			reply := make(chan error)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case waits <- reply:
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-reply:
+				return err
+			}
+		}
+		options = run.DebounceOptions{
+			Wait:     time.Second,
+			Trailing: true,
+		}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	JustBeforeEach(func() {
+		fn = run.DebounceWaiter(options, waiter, func(ctx context.Context) error {
+			atomic.AddInt64(&counter, 1)
+			return nil
+		})
+	})
+	executions := func() int64 {
+		return atomic.LoadInt64(&counter)
+	}
+	release := func() {
+		var reply chan error
+		Eventually(waits).Should(Receive(&reply))
+		reply <- nil
+	}
+	It("collapses a burst into one trailing execution", func() {
+		Expect(fn(ctx)).To(BeNil())
+		var reply chan error
+		Eventually(waits).Should(Receive(&reply))
+		Expect(fn(ctx)).To(BeNil())
+		Expect(fn(ctx)).To(BeNil())
+		reply <- nil
+		Expect(executions()).To(Equal(int64(0)))
+		release()
+		Eventually(executions).Should(Equal(int64(1)))
+		Consistently(executions, 50*time.Millisecond).Should(Equal(int64(1)))
+		Expect(waiter.WaitCallCount()).To(Equal(2))
+	})
+	It("executes again for a new burst", func() {
+		Expect(fn(ctx)).To(BeNil())
+		release()
+		Eventually(executions).Should(Equal(int64(1)))
+		Expect(fn(ctx)).To(BeNil())
+		release()
+		Eventually(executions).Should(Equal(int64(2)))
+	})
+	It("drops the pending execution if the context is canceled", func() {
+		Expect(fn(ctx)).To(BeNil())
+		Eventually(waits).Should(Receive())
+		cancel()
+		Consistently(executions, 50*time.Millisecond).Should(Equal(int64(0)))
+	})
+	Context("with leading", func() {
+		BeforeEach(func() {
+			options.Leading = true
+			options.Trailing = false
+		})
+		It("executes on the first call only", func() {
+			Expect(fn(ctx)).To(BeNil())
+			Eventually(executions).Should(Equal(int64(1)))
+			var reply chan error
+			Eventually(waits).Should(Receive(&reply))
+			Expect(fn(ctx)).To(BeNil())
+			reply <- nil
+			release()
+			Consistently(executions, 50*time.Millisecond).Should(Equal(int64(1)))
+		})
+	})
+	Context("with max wait", func() {
+		BeforeEach(func() {
+			options.MaxWait = 2 * time.Second
+		})
+		It("executes while calls keep arriving", func() {
+			Expect(fn(ctx)).To(BeNil())
+			var reply chan error
+			Eventually(waits).Should(Receive(&reply))
+			Expect(fn(ctx)).To(BeNil())
+			reply <- nil
+			Eventually(waits).Should(Receive(&reply))
+			Expect(fn(ctx)).To(BeNil())
+			reply <- nil
+			Eventually(executions).Should(Equal(int64(1)))
+			release()
+			Consistently(executions, 50*time.Millisecond).Should(Equal(int64(1)))
+		})
+		It("shortens the last window to the max wait", func() {
+			options.Wait = 500 * time.Millisecond
+			options.MaxWait = 1200 * time.Millisecond
+			fn = run.DebounceWaiter(options, waiter, func(ctx context.Context) error {
+				atomic.AddInt64(&counter, 1)
+				return nil
+			})
+			Expect(fn(ctx)).To(BeNil())
+			for i := 0; i < 3; i++ {
+				var reply chan error
+				Eventually(waits).Should(Receive(&reply))
+				Expect(fn(ctx)).To(BeNil())
+				reply <- nil
+			}
+			Eventually(executions).Should(Equal(int64(1)))
+			_, first := waiter.WaitArgsForCall(0)
+			_, second := waiter.WaitArgsForCall(1)
+			_, third := waiter.WaitArgsForCall(2)
+			Expect(first).To(Equal(500 * time.Millisecond))
+			Expect(second).To(Equal(500 * time.Millisecond))
+			Expect(third).To(Equal(200 * time.Millisecond))
+		})
+		It("waits at most the max wait", func() {
+			options.MaxWait = 100 * time.Millisecond
+			fn = run.DebounceWaiter(options, waiter, func(ctx context.Context) error {
+				atomic.AddInt64(&counter, 1)
+				return nil
+			})
+			Expect(fn(ctx)).To(BeNil())
+			release()
+			Eventually(executions).Should(Equal(int64(1)))
+			_, wait := waiter.WaitArgsForCall(0)
+			Expect(wait).To(Equal(100 * time.Millisecond))
+		})
+	})
+})
+
+type fireFunc func()
+
+func (f fireFunc) Fire() {
+	f()
+}
+
+var _ = Describe("DebounceFire", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var waiter *mocks.Waiter
+	var release chan struct{}
+	var trigger run.Trigger
+	var counter int64
+	var fire run.Fire
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		release = make(chan struct{})
+		release := release
+		waiter = &mocks.Waiter{}
+		waiter.WaitStub = func(ctx context.Context, wait time.Duration) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-release:
+				return nil
+			}
+		}
+		trigger = run.NewTrigger()
+		counter = 0
+		fire = run.DebounceFireWaiter(ctx, run.DebounceOptions{
+			Wait:     time.Second,
+			Trailing: true,
+		}, waiter, fireFunc(func() {
+			atomic.AddInt64(&counter, 1)
+			trigger.Fire()
+		}))
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	fires := func() int64 {
+		return atomic.LoadInt64(&counter)
+	}
+	It("collapses a burst of fires into one trailing fire", func() {
+		fire.Fire()
+		fire.Fire()
+		fire.Fire()
+		Consistently(trigger.Done(), 50*time.Millisecond).ShouldNot(BeClosed())
+		close(release)
+		Eventually(trigger.Done()).Should(BeClosed())
+		Consistently(fires, 50*time.Millisecond).Should(Equal(int64(1)))
+	})
+	It("drops the pending fire if the context is canceled", func() {
+		fire.Fire()
+		Eventually(waiter.WaitCallCount).Should(Equal(1))
+		cancel()
+		Consistently(trigger.Done(), 50*time.Millisecond).ShouldNot(BeClosed())
+	})
+	It("works with Triggered", func() {
+		var executions int64
+		fn := run.Triggered(func(ctx context.Context) error {
+			atomic.AddInt64(&executions, 1)
+			return nil
+		}, trigger.Done())
+		fire.Fire()
+		fire.Fire()
+		close(release)
+		Expect(fn(ctx)).To(Succeed())
+		Expect(atomic.LoadInt64(&executions)).To(Equal(int64(1)))
+	})
+})
+
+var _ = Describe("Throttle", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var waiter *mocks.Waiter
+	var counter int64
+	var fn run.Func
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		counter = 0
+		waiter = &mocks.Waiter{}
+		fn = run.ThrottleWaiter(time.Second, waiter, func(ctx context.Context) error {
+			atomic.AddInt64(&counter, 1)
+			return nil
+		})
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	executions := func() int64 {
+		return atomic.LoadInt64(&counter)
+	}
+	It("executes leading and trailing", func() {
+		release := make(chan struct{})
+		waiter.WaitStub = func(ctx context.Context, wait time.Duration) error {
+			Expect(wait).To(Equal(time.Second))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-release:
+				return nil
+			}
+		}
+		Expect(fn(ctx)).To(BeNil())
+		Eventually(executions).Should(Equal(int64(1)))
+		Expect(fn(ctx)).To(BeNil())
+		Expect(fn(ctx)).To(BeNil())
+		Expect(fn(ctx)).To(BeNil())
+		release <- struct{}{}
+		Eventually(executions).Should(Equal(int64(2)))
+		Consistently(executions, 50*time.Millisecond).Should(Equal(int64(2)))
+	})
+	It("keeps a cooldown after a trailing execution", func() {
+		waits := make(chan chan error)
+		waiter.WaitStub = func(ctx context.Context, wait time.Duration) error {
+			reply := make(chan error)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case waits <- reply:
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-reply:
+				return err
+			}
+		}
+		slow := make(chan struct{})
+		fn = run.ThrottleWaiter(time.Second, waiter, func(ctx context.Context) error {
+			if atomic.AddInt64(&counter, 1) == 1 {
+				<-slow
+			}
+			return nil
+		})
+		Expect(fn(ctx)).To(BeNil())
+		Eventually(executions).Should(Equal(int64(1)))
+
+		// call arrives while the leading execution is still running
+		Expect(fn(ctx)).To(BeNil())
+		close(slow)
+
+		var reply chan error
+		Eventually(waits).Should(Receive(&reply))
+		reply <- nil
+		Eventually(executions).Should(Equal(int64(2)))
+
+		// call right after the trailing execution waits for the cooldown
+		Eventually(waits).Should(Receive(&reply))
+		Expect(fn(ctx)).To(BeNil())
+		Consistently(executions, 50*time.Millisecond).Should(Equal(int64(2)))
+		reply <- nil
+		Eventually(executions).Should(Equal(int64(3)))
+	})
+	It("works with the default waiter", func() {
+		fn = run.Throttle(10*time.Millisecond, func(ctx context.Context) error {
+			atomic.AddInt64(&counter, 1)
+			return nil
+		})
+		for i := 0; i < 10; i++ {
+			Expect(fn(ctx)).To(BeNil())
+		}
+		Eventually(executions).Should(Equal(int64(2)))
+		Consistently(executions, 50*time.Millisecond).Should(Equal(int64(2)))
+	})
+})
@@ -12,11 +12,19 @@ import (
 // Delayed wraps the given function to delay its execution by the specified duration.
 // The delay is implemented using a timer and respects context cancellation.
 func Delayed(fn Func, duration time.Duration) Func {
+	return DelayedWithClock(fn, duration, nil)
+}
+
+// DelayedWithClock wraps the given function like Delayed using a timer of the given clock.
+// If clock is nil, the DefaultClock is used.
+func DelayedWithClock(fn Func, duration time.Duration, clock Clock) Func {
 	return func(ctx context.Context) error {
+		timer := orDefaultClock(clock).NewTimer(duration)
+		defer timer.Stop()
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.NewTimer(duration).C:
+		case <-timer.C():
 			return fn(ctx)
 		}
 	}
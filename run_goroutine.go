@@ -0,0 +1,23 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// GoroutineLabel is the pprof label key set on all goroutines spawned by this package.
+// Its value names the primitive that spawned the goroutine, e.g. "Run" or "BackgroundRunner".
+// Goroutines started by functions running in such a goroutine inherit the label.
+const GoroutineLabel = "run"
+
+// goLabeled runs fn in a new goroutine labeled with the name of the spawning primitive.
+// Labels already set on ctx, e.g. by the caller with pprof.Do, are kept.
+func goLabeled(ctx context.Context, name string, fn func()) {
+	go pprof.Do(ctx, pprof.Labels(GoroutineLabel, name), func(context.Context) {
+		fn()
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("GoroutineLabel", func() {
+	var labels string
+	BeforeEach(func() {
+		labels = ""
+		pprof.Do(
+			context.Background(),
+			pprof.Labels("caller", "value"),
+			func(ctx context.Context) {
+				err := run.All(ctx, func(ctx context.Context) error {
+					labels = goroutineLabels("run_goroutine_test.go")
+					return nil
+				})
+				Expect(err).To(BeNil())
+			},
+		)
+	})
+	It("labels the spawned goroutine with the primitive", func() {
+		Expect(labels).To(ContainSubstring(`"run":"Run"`))
+	})
+	It("keeps the labels set by the caller", func() {
+		Expect(labels).To(ContainSubstring(`"caller":"value"`))
+	})
+})
+
+// goroutineLabels returns the labels line of the goroutine profile group whose stack contains file.
+func goroutineLabels(file string) string {
+	var buf bytes.Buffer
+	Expect(pprof.Lookup("goroutine").WriteTo(&buf, 1)).To(Succeed())
+	for _, block := range strings.Split(buf.String(), "\n\n") {
+		if !strings.Contains(block, file) {
+			continue
+		}
+		for _, line := range strings.Split(block, "\n") {
+			if labels, ok := strings.CutPrefix(line, "# labels: "); ok {
+				return labels
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// HealthComponentStatus is the health of a single component.
+type HealthComponentStatus struct {
+	Live        bool      `json:"live"`
+	Ready       bool      `json:"ready"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// HealthStatus is the aggregated health of all components.
+type HealthStatus struct {
+	Live       bool                             `json:"live"`
+	Ready      bool                             `json:"ready"`
+	Components map[string]HealthComponentStatus `json:"components"`
+}
+
+// HealthRegistry aggregates the liveness and readiness of named components.
+// It serves /healthz (liveness) and /readyz (readiness) as JSON, responding
+// with 200 if all components are healthy and 503 otherwise.
+type HealthRegistry interface {
+	http.Handler
+	// Supervise wraps a long-running function. The component is live and ready while
+	// the function runs and unhealthy once it returned or panicked.
+	Supervise(name string, fn Func) Func
+	// Track wraps a repeatedly executed function. The component becomes ready with the first
+	// success and is unhealthy if it did not succeed within staleness.
+	Track(name string, staleness time.Duration, fn Func) Func
+	// SetLive reports the liveness of a component, the error describes why it is not live.
+	SetLive(name string, err error)
+	// SetReady reports the readiness of a component.
+	SetReady(name string, ready bool)
+	// Status returns the current health of all components.
+	Status() HealthStatus
+	// LivenessHandler returns a handler serving the liveness.
+	LivenessHandler() http.Handler
+	// ReadinessHandler returns a handler serving the readiness.
+	ReadinessHandler() http.Handler
+}
+
+// NewHealthRegistry creates a new empty HealthRegistry.
+func NewHealthRegistry() HealthRegistry {
+	return NewHealthRegistryWithClock(nil)
+}
+
+// NewHealthRegistryWithClock creates a new empty HealthRegistry that uses the given clock for staleness.
+// If clock is nil, the DefaultClock is used.
+func NewHealthRegistryWithClock(clock Clock) HealthRegistry {
+	return &healthRegistry{
+		clock:      clock,
+		components: map[string]*healthComponent{},
+	}
+}
+
+type healthComponent struct {
+	live        bool
+	ready       bool
+	err         string
+	staleness   time.Duration
+	since       time.Time
+	lastSuccess time.Time
+}
+
+func (h *healthComponent) status(now time.Time) HealthComponentStatus {
+	result := HealthComponentStatus{
+		Live:        h.live,
+		Ready:       h.live && h.ready,
+		LastSuccess: h.lastSuccess,
+		Error:       h.err,
+	}
+	if h.live && h.staleness > 0 {
+		last := h.lastSuccess
+		if last.IsZero() {
+			last = h.since
+		}
+		if now.Sub(last) > h.staleness {
+			result.Live = false
+			result.Ready = false
+			result.Error = "no success within " + h.staleness.String()
+		}
+	}
+	return result
+}
+
+type healthRegistry struct {
+	clock      Clock
+	mux        sync.Mutex
+	components map[string]*healthComponent
+}
+
+func (h *healthRegistry) Supervise(name string, fn Func) Func {
+	h.update(name, func(c *healthComponent) {
+		c.err = "not started"
+	})
+	return func(ctx context.Context) (err error) {
+		h.update(name, func(c *healthComponent) {
+			c.live = true
+			c.ready = true
+			c.err = ""
+		})
+		panicked := true
+		// deferred so a panicking function is marked unhealthy as well
+		defer h.update(name, func(c *healthComponent) {
+			c.live = false
+			c.ready = false
+			switch {
+			case panicked:
+				c.err = "panicked"
+			case err != nil:
+				c.err = err.Error()
+			default:
+				c.err = "exited"
+			}
+		})
+		err = fn(ctx)
+		panicked = false
+		return err
+	}
+}
+
+func (h *healthRegistry) Track(name string, staleness time.Duration, fn Func) Func {
+	h.update(name, func(c *healthComponent) {
+		c.live = true
+		c.staleness = staleness
+		c.since = orDefaultClock(h.clock).Now()
+	})
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			h.update(name, func(c *healthComponent) {
+				c.err = err.Error()
+			})
+			return err
+		}
+		h.update(name, func(c *healthComponent) {
+			c.ready = true
+			c.err = ""
+			c.lastSuccess = orDefaultClock(h.clock).Now()
+		})
+		return nil
+	}
+}
+
+func (h *healthRegistry) SetLive(name string, err error) {
+	h.update(name, func(c *healthComponent) {
+		c.live = err == nil
+		c.err = ""
+		if err != nil {
+			c.err = err.Error()
+		}
+	})
+}
+
+func (h *healthRegistry) SetReady(name string, ready bool) {
+	h.update(name, func(c *healthComponent) {
+		c.ready = ready
+	})
+}
+
+func (h *healthRegistry) update(name string, fn func(c *healthComponent)) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	component, ok := h.components[name]
+	if !ok {
+		component = &healthComponent{
+			live: true,
+		}
+		h.components[name] = component
+	}
+	fn(component)
+}
+
+func (h *healthRegistry) Status() HealthStatus {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	now := orDefaultClock(h.clock).Now()
+	result := HealthStatus{
+		Live:       true,
+		Ready:      true,
+		Components: make(map[string]HealthComponentStatus, len(h.components)),
+	}
+	for name, component := range h.components {
+		status := component.status(now)
+		result.Components[name] = status
+		result.Live = result.Live && status.Live
+		result.Ready = result.Ready && status.Ready
+	}
+	return result
+}
+
+func (h *healthRegistry) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/healthz"):
+		h.LivenessHandler().ServeHTTP(resp, req)
+	case strings.HasSuffix(req.URL.Path, "/readyz"):
+		h.ReadinessHandler().ServeHTTP(resp, req)
+	default:
+		http.NotFound(resp, req)
+	}
+}
+
+func (h *healthRegistry) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		status := h.Status()
+		writeHealthStatus(resp, status, status.Live)
+	})
+}
+
+func (h *healthRegistry) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		status := h.Status()
+		writeHealthStatus(resp, status, status.Ready)
+	})
+}
+
+func writeHealthStatus(resp http.ResponseWriter, status HealthStatus, healthy bool) {
+	resp.Header().Set("Content-Type", "application/json")
+	if healthy {
+		resp.WriteHeader(http.StatusOK)
+	} else {
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(resp).Encode(status); err != nil {
+		glog.Warningf("encode health status failed: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("HealthRegistry", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var registry run.HealthRegistry
+	var server *httptest.Server
+	get := func(path string) (int, run.HealthStatus) {
+		resp, err := http.Get(server.URL + path)
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		var status run.HealthStatus
+		if resp.StatusCode != http.StatusNotFound {
+			Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(json.NewDecoder(resp.Body).Decode(&status)).To(BeNil())
+		}
+		return resp.StatusCode, status
+	}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		registry = run.NewHealthRegistry()
+		server = httptest.NewServer(registry)
+	})
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+	It("is healthy without components", func() {
+		code, status := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(status.Live).To(BeTrue())
+		code, _ = get("/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+	})
+	It("returns not found for unknown paths", func() {
+		code, _ := get("/banana")
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+	It("reports manual liveness and readiness", func() {
+		registry.SetReady("db", false)
+		code, _ := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+		code, status := get("/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(status.Components).To(HaveKeyWithValue("db", HaveField("Ready", BeFalse())))
+
+		registry.SetReady("db", true)
+		code, _ = get("/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+
+		registry.SetLive("db", stderrors.New("connection lost"))
+		code, status = get("/healthz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(status.Components["db"].Error).To(Equal("connection lost"))
+		code, _ = get("/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+	})
+	Context("Supervise", func() {
+		It("is unhealthy once the function exited", func() {
+			release := make(chan struct{})
+			fn := registry.Supervise("worker", func(ctx context.Context) error {
+				<-release
+				return stderrors.New("banana")
+			})
+			code, _ := get("/readyz")
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- fn(ctx)
+			}()
+			Eventually(func() bool { return registry.Status().Ready }).Should(BeTrue())
+			code, _ = get("/healthz")
+			Expect(code).To(Equal(http.StatusOK))
+
+			close(release)
+			Eventually(errs).Should(Receive(MatchError("banana")))
+			code, status := get("/healthz")
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+			Expect(status.Components["worker"].Error).To(Equal("banana"))
+		})
+		It("is unhealthy once the function panicked", func() {
+			fn := registry.Supervise("worker", func(ctx context.Context) error {
+				panic("banana")
+			})
+			Expect(func() { _ = fn(ctx) }).To(PanicWith("banana"))
+			code, status := get("/healthz")
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+			Expect(status.Components["worker"].Error).To(Equal("panicked"))
+			Expect(status.Components["worker"].Ready).To(BeFalse())
+		})
+	})
+	Context("Track", func() {
+		It("is unhealthy if the function did not succeed within staleness", func() {
+			var result error
+			fn := registry.Track("job", 100*time.Millisecond, func(ctx context.Context) error {
+				return result
+			})
+			code, _ := get("/healthz")
+			Expect(code).To(Equal(http.StatusOK))
+			code, _ = get("/readyz")
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+
+			Expect(fn(ctx)).To(BeNil())
+			code, status := get("/readyz")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(status.Components["job"].LastSuccess).NotTo(BeZero())
+
+			result = stderrors.New("banana")
+			Expect(fn(ctx)).To(MatchError("banana"))
+			Expect(registry.Status().Components["job"].Error).To(Equal("banana"))
+			Eventually(func() bool { return registry.Status().Live }).Should(BeFalse())
+			code, _ = get("/healthz")
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+
+			result = nil
+			Expect(fn(ctx)).To(BeNil())
+			code, _ = get("/healthz")
+			Expect(code).To(Equal(http.StatusOK))
+		})
+	})
+})
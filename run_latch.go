@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// Latch is a countdown latch that is done once it was counted down to zero.
+// Once done it stays done, further count downs are ignored.
+type Latch interface {
+	Done
+	// CountDown decrements the count and closes the Done channel when it reaches zero.
+	CountDown()
+	// Count returns the remaining count.
+	Count() int
+	// Wait waits until the count reached zero or the context is canceled.
+	Wait(ctx context.Context) error
+}
+
+// NewLatch creates a new Latch with the given count. A count of zero or less is done immediately.
+func NewLatch(count int) Latch {
+	l := &latch{
+		count: max(count, 0),
+		done:  NewTrigger(),
+	}
+	if l.count == 0 {
+		l.done.Fire()
+	}
+	return l
+}
+
+type latch struct {
+	mux   sync.Mutex
+	count int
+	done  Trigger
+}
+
+func (l *latch) CountDown() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.count == 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		l.done.Fire()
+	}
+}
+
+func (l *latch) Count() int {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return l.count
+}
+
+func (l *latch) Done() <-chan struct{} {
+	return l.done.Done()
+}
+
+func (l *latch) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.done.Done():
+		return nil
+	}
+}
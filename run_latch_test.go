@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("Latch", func() {
+	var ctx context.Context
+	var latch run.Latch
+	BeforeEach(func() {
+		ctx = context.Background()
+		latch = run.NewLatch(2)
+	})
+	It("is done after counting down to zero", func() {
+		Expect(latch.Count()).To(Equal(2))
+		latch.CountDown()
+		Expect(latch.Done()).NotTo(BeClosed())
+		latch.CountDown()
+		Expect(latch.Done()).To(BeClosed())
+		Expect(latch.Count()).To(Equal(0))
+		Expect(latch.Wait(ctx)).To(Succeed())
+	})
+	It("ignores count downs after done", func() {
+		latch.CountDown()
+		latch.CountDown()
+		latch.CountDown()
+		Expect(latch.Count()).To(Equal(0))
+		Expect(latch.Done()).To(BeClosed())
+	})
+	It("is done immediately with count zero", func() {
+		Expect(run.NewLatch(0).Done()).To(BeClosed())
+		Expect(run.NewLatch(-1).Count()).To(Equal(0))
+	})
+	It("stops waiting if the context is canceled", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(latch.Wait(ctx)).To(MatchError(context.Canceled))
+	})
+	It("works with Triggered", func() {
+		var called bool
+		fn := run.Triggered(func(ctx context.Context) error {
+			called = true
+			return nil
+		}, latch.Done())
+		latch.CountDown()
+		latch.CountDown()
+		Expect(fn(ctx)).To(Succeed())
+		Expect(called).To(BeTrue())
+	})
+})
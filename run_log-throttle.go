@@ -0,0 +1,136 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrorLogThrottleOptions configures LogErrorsThrottled and SkipErrorsThrottled.
+type ErrorLogThrottleOptions struct {
+	// Window in which repeats of the same error are suppressed.
+	Window time.Duration
+	// Fingerprint identifies equal errors. If nil, ErrorFingerprint is used.
+	Fingerprint func(err error) string
+	// Logger is used for logging. If nil, the DefaultLogger is used.
+	Logger Logger
+	// Clock provides the time and the timer of the window. If nil, the DefaultClock is used.
+	Clock Clock
+}
+
+// LogErrorsThrottled wraps the given function like LogErrors but logs only the first occurrence of an error.
+// Repeats of the same error within the window are suppressed and summarized
+// ("repeated 523 times in 5m0s") once the window closes or a different error occurs.
+func LogErrorsThrottled(options ErrorLogThrottleOptions, fn Func) Func {
+	throttle := newErrorLogThrottle(options, funcName(fn))
+	return func(ctx context.Context) error {
+		start := throttle.options.Clock.Now()
+		if err := fn(ctx); err != nil {
+			throttle.log(ctx, start, err)
+			return err
+		}
+		return nil
+	}
+}
+
+// SkipErrorsThrottled wraps the given function like SkipErrors but throttles the logged errors like LogErrorsThrottled.
+func SkipErrorsThrottled(options ErrorLogThrottleOptions, fn Func) Func {
+	logErrors := LogErrorsThrottled(options, fn)
+	return func(ctx context.Context) error {
+		_ = logErrors(ctx)
+		return nil
+	}
+}
+
+func newErrorLogThrottle(options ErrorLogThrottleOptions, name string) *errorLogThrottle {
+	if options.Fingerprint == nil {
+		options.Fingerprint = ErrorFingerprint
+	}
+	options.Clock = orDefaultClock(options.Clock)
+	return &errorLogThrottle{
+		options: options,
+		name:    name,
+	}
+}
+
+type errorLogThrottle struct {
+	options ErrorLogThrottleOptions
+	name    string
+
+	mux         sync.Mutex
+	ctx         context.Context
+	fingerprint string
+	err         error
+	first       time.Time
+	repeated    int
+	timer       Timer
+	stop        chan struct{}
+}
+
+// log logs the error if it is the first occurrence within the window and counts it otherwise.
+func (e *errorLogThrottle) log(ctx context.Context, start time.Time, err error) {
+	fingerprint := e.options.Fingerprint(err)
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if e.timer != nil && fingerprint == e.fingerprint {
+		e.repeated++
+		e.ctx = ctx
+		return
+	}
+	e.flush()
+	orDefaultLogger(e.options.Logger).Warn(
+		ctx,
+		"run failed",
+		runFailedArgs(e.name, e.options.Clock.Now().Sub(start), err)...,
+	)
+	e.ctx = ctx
+	e.fingerprint = fingerprint
+	e.err = err
+	e.first = e.options.Clock.Now()
+	e.repeated = 0
+
+	timer := e.options.Clock.NewTimer(e.options.Window)
+	stop := make(chan struct{})
+	e.timer = timer
+	e.stop = stop
+	goLabeled(ctx, "ErrorLogThrottle", func() {
+		select {
+		case <-stop:
+		case <-timer.C():
+			e.mux.Lock()
+			defer e.mux.Unlock()
+			if e.timer == timer {
+				e.flush()
+			}
+		}
+	})
+}
+
+// flush closes the current window and logs the summary of suppressed repeats, the caller must hold the lock.
+func (e *errorLogThrottle) flush() {
+	if e.timer == nil {
+		return
+	}
+	e.timer.Stop()
+	close(e.stop)
+	e.timer = nil
+	if e.repeated == 0 {
+		return
+	}
+	elapsed := e.options.Clock.Now().Sub(e.first).Round(time.Second)
+	orDefaultLogger(e.options.Logger).Warn(
+		context.WithoutCancel(e.ctx),
+		fmt.Sprintf("run failed: repeated %d times in %v", e.repeated, elapsed),
+		append(
+			[]any{"func", e.name, "count", e.repeated, "window", elapsed, "error", e.err},
+			errorDataArgs(e.err)...,
+		)...,
+	)
+	e.repeated = 0
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("LogErrorsThrottled", func() {
+	var ctx context.Context
+	var buf *syncBuffer
+	var options run.ErrorLogThrottleOptions
+	var result error
+	var fn run.Func
+	BeforeEach(func() {
+		ctx = context.Background()
+		buf = &syncBuffer{}
+		options = run.ErrorLogThrottleOptions{
+			Window: time.Hour,
+			Logger: run.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, nil))),
+		}
+		result = stderrors.New("banana")
+	})
+	JustBeforeEach(func() {
+		fn = run.LogErrorsThrottled(options, func(ctx context.Context) error {
+			return result
+		})
+	})
+	It("returns the error", func() {
+		Expect(fn(ctx)).To(Equal(result))
+	})
+	It("logs nothing on success", func() {
+		result = nil
+		Expect(fn(ctx)).To(BeNil())
+		Expect(buf.Records()).To(BeEmpty())
+	})
+	It("logs only the first occurrence within the window", func() {
+		for i := 0; i < 5; i++ {
+			Expect(fn(ctx)).NotTo(BeNil())
+		}
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "run failed"))
+		Expect(records[0]).To(HaveKeyWithValue("error", "banana"))
+	})
+	It("logs a summary when the error changes", func() {
+		for i := 0; i < 4; i++ {
+			Expect(fn(ctx)).NotTo(BeNil())
+		}
+		result = stderrors.New("apple")
+		Expect(fn(ctx)).NotTo(BeNil())
+
+		records := buf.Records()
+		Expect(records).To(HaveLen(3))
+		Expect(records[1]).To(HaveKeyWithValue("msg", "run failed: repeated 3 times in 0s"))
+		Expect(records[1]).To(HaveKeyWithValue("count", 3.0))
+		Expect(records[1]).To(HaveKeyWithValue("error", "banana"))
+		Expect(records[2]).To(HaveKeyWithValue("error", "apple"))
+	})
+	Context("short window", func() {
+		BeforeEach(func() {
+			options.Window = 20 * time.Millisecond
+		})
+		It("logs a summary when the window closes", func() {
+			Expect(fn(ctx)).NotTo(BeNil())
+			Expect(fn(ctx)).NotTo(BeNil())
+			Eventually(buf.Records).Should(HaveLen(2))
+			Expect(buf.Records()[1]).To(HaveKeyWithValue("count", 1.0))
+		})
+		It("logs no summary without repeats", func() {
+			Expect(fn(ctx)).NotTo(BeNil())
+			Consistently(buf.Records, 50*time.Millisecond).Should(HaveLen(1))
+		})
+		It("logs the error again after the window", func() {
+			Expect(fn(ctx)).NotTo(BeNil())
+			time.Sleep(40 * time.Millisecond)
+			Expect(fn(ctx)).NotTo(BeNil())
+			Expect(buf.Records()).To(HaveLen(2))
+			Expect(buf.Records()[1]).To(HaveKeyWithValue("msg", "run failed"))
+		})
+	})
+	It("skips errors with SkipErrorsThrottled", func() {
+		skip := run.SkipErrorsThrottled(options, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(skip(ctx)).To(BeNil())
+		Expect(skip(ctx)).To(BeNil())
+		Expect(buf.Records()).To(HaveLen(1))
+	})
+})
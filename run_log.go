@@ -6,19 +6,26 @@ package run
 
 import (
 	"context"
-
-	"github.com/golang/glog"
+	"time"
 )
 
 // LogErrors wraps the given function to log any errors that occur while still propagating them.
 // Errors are logged as warnings and then returned to the caller.
 func LogErrors(fn Func) Func {
+	return LogErrorsWithLogger(nil, fn)
+}
+
+// LogErrorsWithLogger wraps the given function like LogErrors and logs errors with the given logger.
+// The warning carries the function name, the duration and the error as attributes.
+// If logger is nil, the DefaultLogger is used.
+func LogErrorsWithLogger(logger Logger, fn Func) Func {
+	name := funcName(fn)
 	return func(ctx context.Context) error {
+		start := time.Now()
 		if err := fn(ctx); err != nil {
-			glog.Warning(err)
+			orDefaultLogger(logger).Warn(ctx, "run failed", runFailedArgs(name, time.Since(start), err)...)
 			return err
 		}
 		return nil
 	}
-
 }
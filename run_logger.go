@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// Logger is the logging interface used by the wrappers of this package.
+// The args are alternating keys and values or slog.Attr, as in log/slog.
+type Logger interface {
+	Debug(ctx context.Context, msg string, args ...any)
+	Info(ctx context.Context, msg string, args ...any)
+	Warn(ctx context.Context, msg string, args ...any)
+	Error(ctx context.Context, msg string, args ...any)
+}
+
+// DefaultLogger is used by all wrappers that have no logger injected.
+var DefaultLogger Logger = NewGlogLogger()
+
+// orDefaultLogger returns the given logger or the DefaultLogger if it is nil.
+func orDefaultLogger(logger Logger) Logger {
+	if logger == nil {
+		return DefaultLogger
+	}
+	return logger
+}
+
+// NewGlogLogger creates a Logger writing to glog. Attributes are appended to the message
+// as key=value pairs and debug messages are logged with verbosity 3.
+func NewGlogLogger() Logger {
+	return &glogLogger{}
+}
+
+type glogLogger struct{}
+
+func (g *glogLogger) Debug(ctx context.Context, msg string, args ...any) {
+	if glog.V(3) {
+		glog.InfoDepth(1, formatLogMessage(msg, args))
+	}
+}
+
+func (g *glogLogger) Info(ctx context.Context, msg string, args ...any) {
+	glog.InfoDepth(1, formatLogMessage(msg, args))
+}
+
+func (g *glogLogger) Warn(ctx context.Context, msg string, args ...any) {
+	glog.WarningDepth(1, formatLogMessage(msg, args))
+}
+
+func (g *glogLogger) Error(ctx context.Context, msg string, args ...any) {
+	glog.ErrorDepth(1, formatLogMessage(msg, args))
+}
+
+// formatLogMessage appends the attributes given as slog args to the message.
+func formatLogMessage(msg string, args []any) string {
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, msg, 0)
+	record.Add(args...)
+	var sb strings.Builder
+	sb.WriteString(msg)
+	record.Attrs(func(attr slog.Attr) bool {
+		fmt.Fprintf(&sb, " %s=%v", attr.Key, attr.Value)
+		return true
+	})
+	return sb.String()
+}
+
+// NewSlogLogger creates a Logger writing structured records to the given slog.Logger.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{
+		logger: logger,
+	}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (s *slogLogger) Debug(ctx context.Context, msg string, args ...any) {
+	s.logger.DebugContext(ctx, msg, args...)
+}
+
+func (s *slogLogger) Info(ctx context.Context, msg string, args ...any) {
+	s.logger.InfoContext(ctx, msg, args...)
+}
+
+func (s *slogLogger) Warn(ctx context.Context, msg string, args ...any) {
+	s.logger.WarnContext(ctx, msg, args...)
+}
+
+func (s *slogLogger) Error(ctx context.Context, msg string, args ...any) {
+	s.logger.ErrorContext(ctx, msg, args...)
+}
+
+// funcName returns the name of the given function for log attributes.
+func funcName(fn Func) string {
+	if fn == nil {
+		return ""
+	}
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
+
+// errorDataArgs returns the data attached to the error with github.com/bborbe/errors
+// as log attributes sorted by key.
+func errorDataArgs(err error) []any {
+	data := errors.DataFromError(err)
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]any, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, slog.String(key, data[key]))
+	}
+	return result
+}
+
+// runFailedArgs returns the log attributes of a failed run including the data of the error.
+func runFailedArgs(name string, duration time.Duration, err error) []any {
+	return append([]any{"func", name, "duration", duration, "error", err}, errorDataArgs(err)...)
+}
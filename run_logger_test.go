@@ -0,0 +1,216 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, stderrors.New("disk full")
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mux sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.buf.Write(p)
+}
+
+// Records returns all JSON log records written so far.
+func (s *syncBuffer) Records() []map[string]interface{} {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(s.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		result = append(result, record)
+	}
+	return result
+}
+
+var _ = Describe("Logger", func() {
+	var ctx context.Context
+	var buf *syncBuffer
+	var logger run.Logger
+	BeforeEach(func() {
+		ctx = context.Background()
+		buf = &syncBuffer{}
+		logger = run.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		})))
+	})
+	It("writes structured records to slog", func() {
+		logger.Warn(ctx, "hello", "key", "value")
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("level", "WARN"))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "hello"))
+		Expect(records[0]).To(HaveKeyWithValue("key", "value"))
+	})
+	It("logs errors of LogErrorsWithLogger with attributes", func() {
+		fn := run.LogErrorsWithLogger(logger, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).NotTo(BeNil())
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "run failed"))
+		Expect(records[0]).To(HaveKeyWithValue("error", "banana"))
+		Expect(records[0]).To(HaveKey("duration"))
+		Expect(records[0]["func"]).To(ContainSubstring("run_test"))
+	})
+	It("logs the data of the error as attributes", func() {
+		fn := run.LogErrorsWithLogger(logger, func(ctx context.Context) error {
+			return errors.AddDataToError(stderrors.New("banana"), map[string]string{
+				"user": "alice",
+			})
+		})
+		Expect(fn(ctx)).NotTo(BeNil())
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("user", "alice"))
+	})
+	It("logs errors of SkipErrorsWithLogger with attributes", func() {
+		fn := run.SkipErrorsWithLogger(logger, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).To(BeNil())
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("error", "banana"))
+	})
+	It("logs failed background runs", func() {
+		runner := run.NewBackgroundRunnerWithOptions(ctx, run.BackgroundRunnerOptions{
+			Logger: logger,
+		})
+		Expect(runner.Run(func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})).To(BeNil())
+		Expect(runner.Wait(ctx)).To(BeNil())
+		Expect(buf.Records()).To(ContainElement(HaveKeyWithValue("error", "banana")))
+	})
+	It("logs skipped parallel runs", func() {
+		skipper := run.NewParallelSkipperWithOptions(run.ParallelSkipperOptions{
+			Logger: logger,
+		})
+		var fn run.Func
+		fn = skipper.SkipParallel(func(ctx context.Context) error {
+			return fn(ctx)
+		})
+		Expect(fn(ctx)).To(BeNil())
+		Expect(buf.Records()).To(ContainElement(
+			HaveKeyWithValue("msg", "skip => already running"),
+		))
+	})
+	It("logs failed debounced runs", func() {
+		fn := run.DebounceWaiter(run.DebounceOptions{
+			Leading: true,
+			Logger:  logger,
+		}, run.NewWaiter(), func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).To(BeNil())
+		Eventually(buf.Records).Should(ContainElement(And(
+			HaveKeyWithValue("msg", "run failed"),
+			HaveKeyWithValue("error", "banana"),
+		)))
+	})
+	It("logs added functions of the concurrent runner", func() {
+		runner := run.NewConcurrentRunnerWithOptions(1, run.ConcurrentRunnerOptions{
+			Logger: logger,
+		})
+		runner.Add(ctx, func(ctx context.Context) error { return nil })
+		Expect(runner.Close()).To(Succeed())
+		Expect(buf.Records()).To(ContainElement(
+			HaveKeyWithValue("msg", "fn add to concurrent runner"),
+		))
+		Expect(buf.Records()).To(ContainElement(
+			HaveKeyWithValue("msg", "close concurrent runner"),
+		))
+	})
+	It("logs the wait duration of the waiter", func() {
+		waiter := run.NewWaiterWithLogger(logger)
+		Expect(waiter.Wait(ctx, time.Millisecond)).To(Succeed())
+		Expect(buf.Records()).To(ContainElement(HaveKeyWithValue("msg", "sleep")))
+	})
+	It("logs failed retry attempts", func() {
+		var counter int
+		fn := run.Retry(run.Backoff{Retries: 1, Logger: logger}, func(ctx context.Context) error {
+			counter++
+			if counter == 1 {
+				return stderrors.New("banana")
+			}
+			return nil
+		})
+		Expect(fn(ctx)).To(BeNil())
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("attempt", 1.0))
+		Expect(records[0]).To(HaveKeyWithValue("error", "banana"))
+	})
+	It("logs errors of SkipErrorsWithReporterWithLogger", func() {
+		memory := run.NewMemoryReporter()
+		fn := run.SkipErrorsWithReporterWithLogger(logger, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, memory, nil)
+		Expect(fn(ctx)).To(BeNil())
+		Expect(memory.Reports()).To(HaveLen(1))
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "run failed"))
+		Expect(records[0]).To(HaveKeyWithValue("error", "banana"))
+	})
+	It("logs reports dropped by rate limit", func() {
+		reporter := run.NewRateLimitedReporter(run.NewMemoryReporter(), run.RateLimitOptions{
+			Window: time.Hour,
+			Logger: logger,
+		})
+		reporter.Report(ctx, stderrors.New("banana"), nil)
+		reporter.Report(ctx, stderrors.New("banana"), nil)
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "report dropped by rate limit"))
+	})
+	It("logs failed writes of the json lines reporter", func() {
+		reporter := run.NewJSONLinesReporterWithLogger(logger, failingWriter{})
+		reporter.Report(ctx, stderrors.New("banana"), nil)
+		records := buf.Records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "write report failed"))
+		Expect(records[0]).To(HaveKeyWithValue("error", "disk full"))
+	})
+	It("uses glog by default", func() {
+		Expect(run.DefaultLogger).NotTo(BeNil())
+		Expect(func() {
+			run.NewGlogLogger().Info(ctx, "hello", "key", "value", slog.Int("count", 1))
+		}).NotTo(Panic())
+	})
+})
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"github.com/bborbe/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsFactory wraps many functions with one family of metrics, distinguished by a job label.
+type MetricsFactory interface {
+	// Metrics wraps the function with metrics labeled with the given job.
+	Metrics(job string, fn Func) Func
+}
+
+// NewMetricsFactory creates a MetricsFactory that collects the metrics of NewMetricsWithOptions
+// with an additional job label. The collectors are registered once; if they have already been
+// registered, for example by another factory with the same options, the existing collectors are reused.
+// It panics if the metrics conflict with differently shaped collectors.
+func NewMetricsFactory(registerer prometheus.Registerer, options MetricsOptions) MetricsFactory {
+	vecs := newMetricsVecs(options, "job")
+	return &metricsFactory{
+		vecs: &metricsVecs{
+			clock:       vecs.clock,
+			started:     registerOrExisting(registerer, vecs.started),
+			completed:   registerOrExisting(registerer, vecs.completed),
+			failed:      registerOrExisting(registerer, vecs.failed),
+			inFlight:    registerOrExisting(registerer, vecs.inFlight),
+			duration:    registerOrExisting(registerer, vecs.duration),
+			lastSuccess: registerOrExisting(registerer, vecs.lastSuccess),
+		},
+	}
+}
+
+type metricsFactory struct {
+	vecs *metricsVecs
+}
+
+func (m *metricsFactory) Metrics(job string, fn Func) Func {
+	return m.vecs.wrap(fn, prometheus.Labels{"job": job})
+}
+
+// registerOrExisting registers the collector or returns the already registered equal collector.
+func registerOrExisting[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	err := registerer.Register(collector)
+	if err == nil {
+		return collector
+	}
+	var alreadyRegisteredError prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegisteredError) {
+		if existing, ok := alreadyRegisteredError.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	panic(err)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("MetricsFactory", func() {
+	var ctx context.Context
+	var registry *prometheus.Registry
+	var options run.MetricsOptions
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry = prometheus.NewRegistry()
+		options = run.MetricsOptions{
+			Namespace: "ns",
+			Subsystem: "jobs",
+		}
+	})
+	It("labels the metrics with the job", func() {
+		factory := run.NewMetricsFactory(registry, options)
+		a := factory.Metrics("a", func(ctx context.Context) error {
+			return nil
+		})
+		b := factory.Metrics("b", func(ctx context.Context) error {
+			return errors.New("banana")
+		})
+		Expect(a(ctx)).To(BeNil())
+		Expect(a(ctx)).To(BeNil())
+		Expect(b(ctx)).NotTo(BeNil())
+
+		Expect(metricValue(registry, "ns_jobs_started_total", map[string]string{"job": "a"})).To(Equal(2.0))
+		Expect(metricValue(registry, "ns_jobs_completed_total", map[string]string{"job": "a"})).To(Equal(2.0))
+		Expect(metricValue(registry, "ns_jobs_started_total", map[string]string{"job": "b"})).To(Equal(1.0))
+		Expect(metricValue(registry, "ns_jobs_failed_total", map[string]string{
+			"job":     "b",
+			"outcome": run.MetricsOutcomeError,
+		})).To(Equal(1.0))
+	})
+	It("reuses already registered collectors", func() {
+		first := run.NewMetricsFactory(registry, options)
+		var second run.MetricsFactory
+		Expect(func() {
+			second = run.NewMetricsFactory(registry, options)
+		}).NotTo(Panic())
+
+		Expect(first.Metrics("a", func(ctx context.Context) error { return nil })(ctx)).To(BeNil())
+		Expect(second.Metrics("a", func(ctx context.Context) error { return nil })(ctx)).To(BeNil())
+		Expect(metricValue(registry, "ns_jobs_completed_total", map[string]string{"job": "a"})).To(Equal(2.0))
+	})
+	It("panics on conflicting collectors", func() {
+		run.NewMetricsWithOptions(registry, options, func(ctx context.Context) error { return nil })
+		Expect(func() {
+			run.NewMetricsFactory(registry, options)
+		}).To(Panic())
+	})
+})
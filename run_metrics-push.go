@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// DefaultMetricsPushTimeout is the timeout of a push used when MetricsPushOptions.PushTimeout is not set.
+const DefaultMetricsPushTimeout = 5 * time.Second
+
+// MetricsPushOptions configures NewMetricsPush.
+type MetricsPushOptions struct {
+	MetricsOptions
+	// URL of the Pushgateway.
+	URL string
+	// Job is the job name the metrics are pushed for.
+	Job string
+	// Grouping labels added to the grouping key of the pushed metrics.
+	Grouping map[string]string
+	// Client is used to send the push request. If nil, http.DefaultClient is used.
+	Client push.HTTPDoer
+	// PushTimeout limits the duration of a push, so an unreachable Pushgateway does not block the job.
+	// If zero, DefaultMetricsPushTimeout is used.
+	PushTimeout time.Duration
+	// ReturnPushError returns the push error if the job itself succeeded.
+	// Errors of the job are always returned unchanged and push errors are only logged.
+	ReturnPushError bool
+}
+
+// NewMetricsPush wraps a function with the metrics of NewMetricsWithOptions and pushes them
+// to a Pushgateway after each run. This allows short-lived batch jobs to report metrics
+// before the process exits. The metrics are added to the grouping with POST, so
+// last_success is only pushed once a run succeeded and a failed run keeps the
+// last_success of earlier runs in the Pushgateway.
+func NewMetricsPush(options MetricsPushOptions, fn Func) Func {
+	vecs := newMetricsVecs(options.MetricsOptions)
+	registry := prometheus.NewRegistry()
+	for _, collector := range vecs.collectors() {
+		if collector != vecs.lastSuccess {
+			registry.MustRegister(collector)
+		}
+	}
+	lastSuccessRegistry := prometheus.NewRegistry()
+	lastSuccessRegistry.MustRegister(vecs.lastSuccess)
+
+	var mux sync.Mutex
+	var succeeded bool
+	wrapped := vecs.wrap(fn, prometheus.Labels{})
+	return func(ctx context.Context) error {
+		err := wrapped(ctx)
+
+		mux.Lock()
+		defer mux.Unlock()
+		gatherers := prometheus.Gatherers{registry}
+		if err == nil {
+			succeeded = true
+		}
+		if succeeded {
+			gatherers = append(gatherers, lastSuccessRegistry)
+		}
+		if pushErr := pushMetrics(ctx, options, gatherers); pushErr != nil {
+			if err != nil || !options.ReturnPushError {
+				glog.Warningf("push metrics of job %s failed: %v", options.Job, pushErr)
+				return err
+			}
+			return pushErr
+		}
+		return err
+	}
+}
+
+func pushMetrics(
+	ctx context.Context,
+	options MetricsPushOptions,
+	gatherer prometheus.Gatherer,
+) error {
+	pusher := push.New(options.URL, options.Job).Gatherer(gatherer)
+	for name, value := range options.Grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	if options.Client != nil {
+		pusher = pusher.Client(options.Client)
+	}
+	timeout := options.PushTimeout
+	if timeout <= 0 {
+		timeout = DefaultMetricsPushTimeout
+	}
+	// push even if the job was canceled, the metrics of the canceled run are still of interest
+	pushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	if err := pusher.AddContext(pushCtx); err != nil {
+		return errors.Wrapf(ctx, err, "push metrics to %s failed", options.URL)
+	}
+	return nil
+}
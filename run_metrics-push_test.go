@@ -0,0 +1,174 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+type pushRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+var _ = Describe("NewMetricsPush", func() {
+	var ctx context.Context
+	var server *httptest.Server
+	var mux sync.Mutex
+	var requests []pushRequest
+	var status int
+	var options run.MetricsPushOptions
+	BeforeEach(func() {
+		ctx = context.Background()
+		mux.Lock()
+		requests = nil
+		status = http.StatusOK
+		mux.Unlock()
+		server = httptest.NewServer(
+			http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				body, _ := io.ReadAll(req.Body)
+				mux.Lock()
+				defer mux.Unlock()
+				requests = append(requests, pushRequest{
+					method: req.Method,
+					path:   req.URL.Path,
+					body:   string(body),
+				})
+				resp.WriteHeader(status)
+			}),
+		)
+		options = run.MetricsPushOptions{
+			MetricsOptions: run.MetricsOptions{
+				Namespace: "ns",
+				Subsystem: "sub",
+			},
+			URL: server.URL,
+			Job: "backup",
+			Grouping: map[string]string{
+				"instance": "db1",
+			},
+		}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("pushes the metrics after a successful run", func() {
+		fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+			return nil
+		})
+		Expect(fn(ctx)).To(BeNil())
+
+		mux.Lock()
+		defer mux.Unlock()
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].method).To(Equal(http.MethodPost))
+		Expect(requests[0].path).To(Equal("/metrics/job/backup/instance/db1"))
+		Expect(requests[0].body).To(ContainSubstring("ns_sub_completed_total"))
+		Expect(requests[0].body).To(ContainSubstring("ns_sub_last_success"))
+	})
+	It("pushes no last_success after a failed run and returns the job error", func() {
+		jobErr := stderrors.New("banana")
+		fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+			return jobErr
+		})
+		Expect(fn(ctx)).To(Equal(jobErr))
+
+		mux.Lock()
+		defer mux.Unlock()
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].body).To(ContainSubstring("ns_sub_failed_total"))
+		Expect(requests[0].body).NotTo(ContainSubstring("ns_sub_last_success"))
+	})
+	It("pushes even if the context is canceled", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		})
+		Expect(fn(ctx)).To(Equal(context.Canceled))
+
+		mux.Lock()
+		defer mux.Unlock()
+		Expect(requests).To(HaveLen(1))
+	})
+	Context("gateway hangs", func() {
+		var hanging *httptest.Server
+		var release chan struct{}
+		BeforeEach(func() {
+			release = make(chan struct{})
+			release := release
+			hanging = httptest.NewServer(
+				http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+					<-release
+				}),
+			)
+			options.URL = hanging.URL
+			options.PushTimeout = 50 * time.Millisecond
+		})
+		AfterEach(func() {
+			close(release)
+			hanging.Close()
+		})
+		It("stops the push after the timeout and returns the job error", func() {
+			options.ReturnPushError = true
+			jobErr := stderrors.New("banana")
+			fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+				return jobErr
+			})
+			errs := make(chan error, 1)
+			go func() {
+				errs <- fn(ctx)
+			}()
+			Eventually(errs, time.Second).Should(Receive(Equal(jobErr)))
+		})
+		It("returns the push error after the timeout if requested", func() {
+			options.ReturnPushError = true
+			fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+				return nil
+			})
+			Expect(fn(ctx)).To(MatchError(ContainSubstring("deadline exceeded")))
+		})
+	})
+	Context("push fails", func() {
+		BeforeEach(func() {
+			mux.Lock()
+			status = http.StatusInternalServerError
+			mux.Unlock()
+		})
+		It("ignores the push error by default", func() {
+			fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+				return nil
+			})
+			Expect(fn(ctx)).To(BeNil())
+		})
+		It("returns the push error if requested", func() {
+			options.ReturnPushError = true
+			fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+				return nil
+			})
+			Expect(fn(ctx)).NotTo(BeNil())
+		})
+		It("never masks the job error", func() {
+			options.ReturnPushError = true
+			jobErr := stderrors.New("banana")
+			fn := run.NewMetricsPush(options, func(ctx context.Context) error {
+				return jobErr
+			})
+			Expect(fn(ctx)).To(Equal(jobErr))
+		})
+	})
+})
@@ -6,7 +6,9 @@ package run
 
 import (
 	"context"
+	"time"
 
+	"github.com/bborbe/errors"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -56,7 +58,164 @@ func NewMetrics(
 			return err
 		}
 		completed.Inc()
-		lastSuccess.SetToCurrentTime()
+		lastSuccess.Set(unixSeconds(DefaultClock.Now()))
 		return nil
 	}
 }
+
+const (
+	// MetricsOutcomeSuccess labels runs that returned no error.
+	MetricsOutcomeSuccess = "success"
+	// MetricsOutcomeError labels runs that returned an error.
+	MetricsOutcomeError = "error"
+	// MetricsOutcomeTimeout labels runs that failed with context.DeadlineExceeded.
+	MetricsOutcomeTimeout = "timeout"
+	// MetricsOutcomeCanceled labels runs that failed with context.Canceled.
+	MetricsOutcomeCanceled = "canceled"
+	// MetricsOutcomePanic labels runs that panicked.
+	MetricsOutcomePanic = "panic"
+)
+
+// MetricsOptions configures the metrics collected by NewMetricsWithOptions.
+type MetricsOptions struct {
+	// Namespace of all metrics.
+	Namespace string
+	// Subsystem of all metrics.
+	Subsystem string
+	// Buckets of the duration histogram in seconds. If nil, prometheus.DefBuckets is used.
+	Buckets []float64
+	// Clock provides the time for durations and last_success. If nil, the DefaultClock is used.
+	Clock Clock
+}
+
+// NewMetricsWithOptions wraps a function with Prometheus metrics collection.
+// It counts started, completed and failed runs, tracks the runs in flight, the timestamp of the
+// last success and a duration histogram. Failed runs and durations are labeled with the outcome
+// (success, error, timeout, canceled or panic). Panics are recorded and propagated.
+func NewMetricsWithOptions(
+	registerer prometheus.Registerer,
+	options MetricsOptions,
+	fn Func,
+) Func {
+	vecs := newMetricsVecs(options)
+	registerer.MustRegister(vecs.collectors()...)
+	return vecs.wrap(fn, prometheus.Labels{})
+}
+
+type metricsVecs struct {
+	clock       Clock
+	started     *prometheus.CounterVec
+	completed   *prometheus.CounterVec
+	failed      *prometheus.CounterVec
+	inFlight    *prometheus.GaugeVec
+	duration    *prometheus.HistogramVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+func newMetricsVecs(options MetricsOptions, labelNames ...string) *metricsVecs {
+	withOutcome := append(append([]string{}, labelNames...), "outcome")
+	return &metricsVecs{
+		clock: options.Clock,
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Subsystem: options.Subsystem,
+			Name:      "started_total",
+			Help:      "Total number of started runs",
+		}, labelNames),
+		completed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Subsystem: options.Subsystem,
+			Name:      "completed_total",
+			Help:      "Total number of successfully completed runs",
+		}, labelNames),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: options.Namespace,
+			Subsystem: options.Subsystem,
+			Name:      "failed_total",
+			Help:      "Total number of failed runs by outcome",
+		}, withOutcome),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: options.Namespace,
+			Subsystem: options.Subsystem,
+			Name:      "in_flight",
+			Help:      "Number of runs currently in flight",
+		}, labelNames),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: options.Namespace,
+			Subsystem: options.Subsystem,
+			Name:      "duration_seconds",
+			Help:      "Duration of runs in seconds by outcome",
+			Buckets:   options.Buckets,
+		}, withOutcome),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: options.Namespace,
+			Subsystem: options.Subsystem,
+			Name:      "last_success",
+			Help:      "Timestamp of last successful run",
+		}, labelNames),
+	}
+}
+
+func (m *metricsVecs) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.started,
+		m.completed,
+		m.failed,
+		m.inFlight,
+		m.duration,
+		m.lastSuccess,
+	}
+}
+
+func (m *metricsVecs) wrap(fn Func, labels prometheus.Labels) Func {
+	started := m.started.With(labels)
+	completed := m.completed.With(labels)
+	failed := m.failed.MustCurryWith(labels)
+	inFlight := m.inFlight.With(labels)
+	duration := m.duration.MustCurryWith(labels)
+	lastSuccess := m.lastSuccess.With(labels)
+	return func(ctx context.Context) (err error) {
+		clock := orDefaultClock(m.clock)
+		start := clock.Now()
+		started.Inc()
+		inFlight.Inc()
+		panicked := true
+		defer func() {
+			inFlight.Dec()
+			outcome := metricsOutcome(err, panicked)
+			duration.WithLabelValues(outcome).Observe(clock.Now().Sub(start).Seconds())
+			if outcome != MetricsOutcomeSuccess {
+				failed.WithLabelValues(outcome).Inc()
+				return
+			}
+			completed.Inc()
+			lastSuccess.Set(unixSeconds(clock.Now()))
+		}()
+		err = fn(ctx)
+		panicked = false
+		return err
+	}
+}
+
+func metricsOutcome(err error, panicked bool) string {
+	var panicError *PanicError
+	switch {
+	case panicked:
+		return MetricsOutcomePanic
+	case errors.As(err, &panicError):
+		return MetricsOutcomePanic
+	case err == nil:
+		return MetricsOutcomeSuccess
+	case errors.Is(err, context.DeadlineExceeded):
+		return MetricsOutcomeTimeout
+	case errors.Is(err, context.Canceled):
+		return MetricsOutcomeCanceled
+	default:
+		return MetricsOutcomeError
+	}
+}
+
+// unixSeconds returns the time as seconds since the epoch like prometheus.Gauge.SetToCurrentTime.
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
@@ -7,6 +7,8 @@ package run_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -58,3 +60,138 @@ var _ = Describe("Metrics", func() {
 		})
 	})
 })
+
+// metricValue returns the value of the counter, gauge or the sample count of the histogram
+// with the given name and labels.
+func metricValue(gatherer prometheus.Gatherer, name string, labels map[string]string) float64 {
+	families, err := gatherer.Gather()
+	Expect(err).To(BeNil())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matches := 0
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value == label.GetValue() {
+					matches++
+				}
+			}
+			if matches != len(labels) {
+				continue
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	Fail(fmt.Sprintf("metric %s with labels %v not found", name, labels))
+	return 0
+}
+
+var _ = Describe("MetricsWithOptions", func() {
+	var ctx context.Context
+	var registry *prometheus.Registry
+	var innerFn run.Func
+	var fn run.Func
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry = prometheus.NewRegistry()
+		innerFn = func(ctx context.Context) error {
+			return nil
+		}
+	})
+	JustBeforeEach(func() {
+		fn = run.NewMetricsWithOptions(registry, run.MetricsOptions{
+			Namespace: "ns",
+			Subsystem: "sub",
+			Buckets:   []float64{0.1, 1},
+		}, func(ctx context.Context) error {
+			return innerFn(ctx)
+		})
+	})
+	It("counts successful runs", func() {
+		Expect(fn(ctx)).To(BeNil())
+		Expect(fn(ctx)).To(BeNil())
+		Expect(metricValue(registry, "ns_sub_started_total", nil)).To(Equal(2.0))
+		Expect(metricValue(registry, "ns_sub_completed_total", nil)).To(Equal(2.0))
+		Expect(metricValue(registry, "ns_sub_in_flight", nil)).To(Equal(0.0))
+		Expect(metricValue(registry, "ns_sub_last_success", nil)).To(BeNumerically(">", 0))
+		Expect(
+			metricValue(registry, "ns_sub_duration_seconds", map[string]string{"outcome": "success"}),
+		).To(Equal(2.0))
+	})
+	It("tracks runs in flight", func() {
+		release := make(chan struct{})
+		innerFn = func(ctx context.Context) error {
+			<-release
+			return nil
+		}
+		done := make(chan error)
+		go func() {
+			done <- fn(ctx)
+		}()
+		Eventually(func() float64 {
+			return metricValue(registry, "ns_sub_in_flight", nil)
+		}).Should(Equal(1.0))
+		close(release)
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(metricValue(registry, "ns_sub_in_flight", nil)).To(Equal(0.0))
+	})
+	DescribeTable("labels failed runs with the outcome",
+		func(err error, outcome string) {
+			innerFn = func(ctx context.Context) error {
+				return err
+			}
+			fn = run.NewMetricsWithOptions(registry, run.MetricsOptions{
+				Namespace: "ns",
+				Subsystem: "table",
+			}, innerFn)
+			Expect(fn(ctx)).To(Equal(err))
+			labels := map[string]string{"outcome": outcome}
+			Expect(metricValue(registry, "ns_table_failed_total", labels)).To(Equal(1.0))
+			Expect(metricValue(registry, "ns_table_duration_seconds", labels)).To(Equal(1.0))
+		},
+		Entry("error", errors.New("banana"), run.MetricsOutcomeError),
+		Entry("timeout", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), run.MetricsOutcomeTimeout),
+		Entry("canceled", context.Canceled, run.MetricsOutcomeCanceled),
+	)
+	It("records panics and propagates them", func() {
+		innerFn = func(ctx context.Context) error {
+			panic("banana")
+		}
+		Expect(func() { _ = fn(ctx) }).To(PanicWith("banana"))
+		labels := map[string]string{"outcome": run.MetricsOutcomePanic}
+		Expect(metricValue(registry, "ns_sub_failed_total", labels)).To(Equal(1.0))
+		Expect(metricValue(registry, "ns_sub_in_flight", nil)).To(Equal(0.0))
+	})
+	It("records recovered panics as panic", func() {
+		innerFn = run.CatchPanic(func(ctx context.Context) error {
+			panic("banana")
+		})
+		Expect(fn(ctx)).NotTo(BeNil())
+		labels := map[string]string{"outcome": run.MetricsOutcomePanic}
+		Expect(metricValue(registry, "ns_sub_failed_total", labels)).To(Equal(1.0))
+	})
+	It("observes the duration", func() {
+		innerFn = func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+		Expect(fn(ctx)).To(BeNil())
+		families, err := registry.Gather()
+		Expect(err).To(BeNil())
+		for _, family := range families {
+			if family.GetName() == "ns_sub_duration_seconds" {
+				histogram := family.GetMetric()[0].GetHistogram()
+				Expect(histogram.GetSampleSum()).To(BeNumerically(">=", 0.01))
+				Expect(histogram.GetBucket()).To(HaveLen(2))
+			}
+		}
+	})
+})
@@ -6,19 +6,110 @@ package run
 
 import (
 	"context"
-
-	"github.com/bborbe/errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
 )
 
+// PanicError is the error of a recovered panic. It carries the recovered value and the
+// stack of the panicking goroutine and can be detected with errors.As.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the formatted stack of the panicking goroutine.
+	Stack []byte
+
+	pcs []uintptr
+}
+
+// NewPanicError creates a PanicError for the recovered value.
+// It must be called in the deferred function that recovered the panic to capture the stack of the panic.
+func NewPanicError(value interface{}) *PanicError {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	return &PanicError{
+		Value: value,
+		Stack: debug.Stack(),
+		pcs:   pcs[:n],
+	}
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("catch panic: %v", p.Value)
+}
+
+// Unwrap returns the recovered value if it is an error.
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// StackTrace returns the program counters of the panicking goroutine.
+// It allows Sentry to attach the stack of the panic to the reported exception.
+func (p *PanicError) StackTrace() []uintptr {
+	return p.pcs
+}
+
 // CatchPanic wraps the given function to recover from panics and convert them to errors.
-// If the wrapped function panics, the panic is recovered and returned as an error.
+// If the wrapped function panics, the panic is recovered and returned as PanicError.
 func CatchPanic(fn Func) Func {
+	return CatchPanicWithOptions(CatchPanicOptions{}, fn)
+}
+
+// CatchPanicOptions configures CatchPanicWithOptions.
+type CatchPanicOptions struct {
+	// Reporter is called with the PanicError of each recovered panic.
+	Reporter Reporter
+	// Tags are passed to the Reporter.
+	Tags map[string]string
+	// RePanic panics again with the recovered value after reporting,
+	// for functions where crashing is the right answer.
+	RePanic bool
+}
+
+// CatchPanicWithOptions wraps the given function like CatchPanic, reports the recovered panic
+// to the configured Reporter and re-panics if requested.
+func CatchPanicWithOptions(options CatchPanicOptions, fn Func) Func {
 	return func(ctx context.Context) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				err = errors.Errorf(ctx, "catch panic: %v", r)
+				panicErr := NewPanicError(r)
+				if options.Reporter != nil {
+					options.Reporter.Report(ctx, panicErr, options.Tags)
+				}
+				if options.RePanic {
+					panic(r)
+				}
+				err = panicErr
 			}
 		}()
 		return fn(ctx)
 	}
 }
+
+type panicRecoveryKey struct{}
+
+// ContextWithPanicRecovery returns a context that makes all goroutines this package spawns with it
+// recover panics. This includes the functions executed by Run, All, CancelOnFirstFinish(Wait),
+// CancelOnFirstError(Wait), ConcurrentRunner, BackgroundRunner, services, shutdown hooks and
+// debounced functions. A recovered panic is returned as PanicError and flows through the
+// normal error handling of the caller instead of crashing the process.
+func ContextWithPanicRecovery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, panicRecoveryKey{}, true)
+}
+
+// PanicRecoveryFromContext returns true if panic recovery was enabled with ContextWithPanicRecovery.
+func PanicRecoveryFromContext(ctx context.Context) bool {
+	enabled, _ := ctx.Value(panicRecoveryKey{}).(bool)
+	return enabled
+}
+
+// catchPanicIfEnabled wraps the function with CatchPanic if panic recovery is enabled in the context.
+func catchPanicIfEnabled(ctx context.Context, fn Func) Func {
+	if PanicRecoveryFromContext(ctx) {
+		return CatchPanic(fn)
+	}
+	return fn
+}
@@ -6,11 +6,15 @@ package run_test
 
 import (
 	"context"
+	stderrors "errors"
 
+	"github.com/bborbe/errors"
+	"github.com/getsentry/sentry-go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/bborbe/run"
+	"github.com/bborbe/run/mocks"
 )
 
 var _ = Describe("CatchPanic", func() {
@@ -32,3 +36,135 @@ var _ = Describe("CatchPanic", func() {
 		Expect(err.Error()).To(Equal("catch panic: banana"))
 	})
 })
+
+var _ = Describe("PanicError", func() {
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+	It("carries the value and the stack", func() {
+		err := run.CatchPanic(func(ctx context.Context) error {
+			panic("banana")
+		})(ctx)
+		var panicErr *run.PanicError
+		Expect(errors.As(errors.Wrap(ctx, err, "wrapped"), &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("banana"))
+		Expect(string(panicErr.Stack)).To(ContainSubstring("run_panic_test.go"))
+		Expect(panicErr.StackTrace()).NotTo(BeEmpty())
+	})
+	It("unwraps error values", func() {
+		cause := stderrors.New("banana")
+		err := run.CatchPanic(func(ctx context.Context) error {
+			panic(cause)
+		})(ctx)
+		Expect(errors.Is(err, cause)).To(BeTrue())
+	})
+	It("reports the panic", func() {
+		memory := run.NewMemoryReporter()
+		err := run.CatchPanicWithOptions(run.CatchPanicOptions{
+			Reporter: memory,
+			Tags:     map[string]string{"a": "b"},
+		}, func(ctx context.Context) error {
+			panic("banana")
+		})(ctx)
+		Expect(err).NotTo(BeNil())
+		reports := memory.Reports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Err).To(Equal(err))
+		Expect(reports[0].Tags).To(Equal(map[string]string{"a": "b"}))
+	})
+	It("re-panics after reporting", func() {
+		memory := run.NewMemoryReporter()
+		fn := run.CatchPanicWithOptions(run.CatchPanicOptions{
+			Reporter: memory,
+			RePanic:  true,
+		}, func(ctx context.Context) error {
+			panic("banana")
+		})
+		Expect(func() { _ = fn(ctx) }).To(PanicWith("banana"))
+		Expect(memory.Reports()).To(HaveLen(1))
+	})
+	It("attaches the stack when reported to sentry", func() {
+		hasCaptureException := &mocks.HasCaptureException{}
+		_ = run.CatchPanicWithOptions(run.CatchPanicOptions{
+			Reporter: run.NewSentryReporter(hasCaptureException),
+		}, func(ctx context.Context) error {
+			panic("banana")
+		})(ctx)
+		Expect(hasCaptureException.CaptureExceptionCallCount()).To(Equal(1))
+		err, hint, scope := hasCaptureException.CaptureExceptionArgsForCall(0)
+		Expect(sentry.ExtractStacktrace(err)).NotTo(BeNil())
+		event := scope.ApplyToEvent(&sentry.Event{}, hint, nil)
+		Expect(event.Contexts).To(HaveKey("panic"))
+		Expect(event.Contexts["panic"]["value"]).To(Equal("banana"))
+		Expect(event.Contexts["panic"]["stack"]).To(ContainSubstring("run_panic_test.go"))
+	})
+})
+
+var _ = Describe("ContextWithPanicRecovery", func() {
+	var ctx context.Context
+	var panicFn run.Func
+	BeforeEach(func() {
+		ctx = run.ContextWithPanicRecovery(context.Background())
+		panicFn = func(ctx context.Context) error {
+			panic("banana")
+		}
+	})
+	isPanicError := func(err error) bool {
+		var panicErr *run.PanicError
+		return errors.As(err, &panicErr)
+	}
+	It("is disabled by default", func() {
+		Expect(run.PanicRecoveryFromContext(context.Background())).To(BeFalse())
+		Expect(run.PanicRecoveryFromContext(ctx)).To(BeTrue())
+	})
+	It("recovers panics in Run", func() {
+		var errs []error
+		for err := range run.Run(ctx, panicFn, func(ctx context.Context) error { return nil }) {
+			errs = append(errs, err)
+		}
+		Expect(errs).To(HaveLen(2))
+		Expect(errs).To(ContainElement(Satisfy(isPanicError)))
+	})
+	It("recovers panics in All", func() {
+		Expect(run.All(ctx, panicFn)).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in CancelOnFirstError", func() {
+		Expect(run.CancelOnFirstError(ctx, panicFn)).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in CancelOnFirstErrorWait", func() {
+		Expect(run.CancelOnFirstErrorWait(ctx, panicFn)).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in CancelOnFirstFinish", func() {
+		Expect(run.CancelOnFirstFinish(ctx, panicFn)).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in CancelOnFirstFinishWait", func() {
+		Expect(run.CancelOnFirstFinishWait(ctx, panicFn)).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in the ConcurrentRunner", func() {
+		runner := run.NewConcurrentRunner(1)
+		runner.Add(ctx, panicFn)
+		Expect(runner.Run(ctx)).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in the BackgroundRunner", func() {
+		memory := run.NewMemoryReporter()
+		runner := run.NewBackgroundRunnerWithOptions(context.Background(), run.BackgroundRunnerOptions{
+			RecoverPanics: true,
+			ErrorHandler: func(ctx context.Context, err error) {
+				memory.Report(ctx, err, nil)
+			},
+		})
+		Expect(runner.Run(panicFn)).To(Succeed())
+		Expect(runner.Wait(ctx)).To(Succeed())
+		reports := memory.Reports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Err).To(Satisfy(isPanicError))
+	})
+	It("recovers panics in services", func() {
+		service := run.NewFuncService("panic", panicFn)
+		Expect(service.Start(ctx)).To(Succeed())
+		Eventually(service.Done()).Should(BeClosed())
+		Expect(service.State()).To(Equal(run.ServiceStateFailed))
+		Expect(service.Err()).To(Satisfy(isPanicError))
+	})
+})
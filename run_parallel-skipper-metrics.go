@@ -0,0 +1,40 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ParallelSkipperMetrics records the behavior of a ParallelSkipper.
+type ParallelSkipperMetrics interface {
+	// Skipped is called when an execution was skipped because the function is already running.
+	Skipped()
+}
+
+// NewParallelSkipperMetrics creates ParallelSkipperMetrics backed by a Prometheus counter registered with the given registerer.
+// Already registered equal collectors are reused.
+func NewParallelSkipperMetrics(
+	registerer prometheus.Registerer,
+	namespace string,
+	subsystem string,
+) ParallelSkipperMetrics {
+	return &parallelSkipperMetrics{
+		skipped: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "parallel_skipper_skipped_total",
+			Help:      "Total number of skipped executions",
+		})),
+	}
+}
+
+type parallelSkipperMetrics struct {
+	skipped prometheus.Counter
+}
+
+func (p *parallelSkipperMetrics) Skipped() {
+	p.skipped.Inc()
+}
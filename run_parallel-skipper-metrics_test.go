@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("ParallelSkipperMetrics", func() {
+	It("records skipped executions", func() {
+		ctx := context.Background()
+		registry := prometheus.NewRegistry()
+		skipper := run.NewParallelSkipperWithMetrics(
+			run.NewParallelSkipperMetrics(registry, "ns", "sub"),
+		)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		fn := skipper.SkipParallel(func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = fn(ctx)
+		}()
+		<-started
+		Expect(fn(ctx)).To(BeNil())
+		Expect(fn(ctx)).To(BeNil())
+		close(release)
+		wg.Wait()
+		Expect(metricValue(registry, "ns_sub_parallel_skipper_skipped_total", nil)).To(Equal(2.0))
+	})
+})
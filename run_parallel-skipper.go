@@ -7,8 +7,7 @@ package run
 import (
 	"context"
 	"sync"
-
-	"github.com/golang/glog"
+	"time"
 )
 
 // ParallelSkipper prevents parallel execution of wrapped functions.
@@ -20,28 +19,65 @@ type ParallelSkipper interface {
 // NewParallelSkipper creates a new ParallelSkipper that can wrap functions to prevent parallel execution.
 // Each ParallelSkipper instance maintains its own execution state.
 func NewParallelSkipper() ParallelSkipper {
-	return &parallelSkipper{}
+	return NewParallelSkipperWithMetrics(nil)
+}
+
+// NewParallelSkipperWithMetrics creates a new ParallelSkipper like NewParallelSkipper
+// that records skipped executions in the given metrics.
+func NewParallelSkipperWithMetrics(metrics ParallelSkipperMetrics) ParallelSkipper {
+	return NewParallelSkipperWithOptions(ParallelSkipperOptions{
+		Metrics: metrics,
+	})
+}
+
+// ParallelSkipperOptions configures a ParallelSkipper.
+type ParallelSkipperOptions struct {
+	// Metrics is an optional recorder for skipped executions.
+	Metrics ParallelSkipperMetrics
+	// Logger is used for logging. If nil, the DefaultLogger is used.
+	Logger Logger
+}
+
+// NewParallelSkipperWithOptions creates a new ParallelSkipper like NewParallelSkipper configured by the given options.
+func NewParallelSkipperWithOptions(options ParallelSkipperOptions) ParallelSkipper {
+	return &parallelSkipper{
+		metrics: options.Metrics,
+		logger:  options.Logger,
+	}
 }
 
 type parallelSkipper struct {
+	metrics ParallelSkipperMetrics
+	logger  Logger
 	running bool
 	mux     sync.Mutex
 }
 
 func (d *parallelSkipper) SkipParallel(action Func) Func {
+	name := funcName(action)
 	return func(ctx context.Context) error {
+		logger := orDefaultLogger(d.logger)
 		d.mux.Lock()
 		if d.running {
-			glog.V(2).Infof("skip => already running")
+			logger.Debug(ctx, "skip => already running", "func", name)
 			d.mux.Unlock()
+			if d.metrics != nil {
+				d.metrics.Skipped()
+			}
 			return nil
 		}
-		glog.V(2).Infof("run started => locked")
+		logger.Debug(ctx, "run started => locked", "func", name)
 		d.running = true
 		d.mux.Unlock()
+		start := time.Now()
 		err := action(ctx)
 		d.mux.Lock()
-		glog.V(2).Infof("run finished => unlocked")
+		logger.Debug(
+			ctx,
+			"run finished => unlocked",
+			"func", name,
+			"duration", time.Since(start),
+		)
 		d.running = false
 		d.mux.Unlock()
 		return err
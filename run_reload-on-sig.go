@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/golang/glog"
+)
+
+// ReloadOnSig returns a function that calls reload each time the process receives SIGHUP.
+// The context is not canceled by the signal, so services can re-read their configuration.
+// It runs until the context is canceled.
+func ReloadOnSig(reload Func) Func {
+	return ReloadOnSignals(reload, syscall.SIGHUP)
+}
+
+// ReloadOnSignals returns a function that calls reload each time the process receives one of the given signals.
+// Signals arriving while reload is running are collapsed into one further call.
+// Failed reloads are logged as warnings and do not stop the function. It runs until the context is canceled.
+// If no signals are given, SIGHUP is used.
+func ReloadOnSignals(reload Func, signals ...os.Signal) Func {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	return func(ctx context.Context) error {
+		signalCh := make(chan os.Signal, 1)
+		signal.Notify(signalCh, signals...)
+		defer signal.Stop(signalCh)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case sig := <-signalCh:
+				glog.V(2).Infof("got signal %s => reload", sig)
+				if err := reload(ctx); err != nil {
+					glog.Warningf("reload failed: %v", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("ReloadOnSig", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var sink chan os.Signal
+	var counter int64
+	var errs chan error
+	var fn run.Func
+
+	sendSignal := func() int64 {
+		process, err := os.FindProcess(os.Getpid())
+		Expect(err).To(BeNil())
+		Expect(process.Signal(syscall.SIGHUP)).To(BeNil())
+		return atomic.LoadInt64(&counter)
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		// keep a handler registered so the signal never terminates the test binary
+		sink = make(chan os.Signal, 10)
+		signal.Notify(sink, syscall.SIGHUP)
+		atomic.StoreInt64(&counter, 0)
+		errs = make(chan error, 1)
+		fn = run.ReloadOnSig(func(ctx context.Context) error {
+			atomic.AddInt64(&counter, 1)
+			return stderrors.New("banana")
+		})
+		go func(ctx context.Context, fn run.Func, errs chan<- error) {
+			errs <- fn(ctx)
+		}(ctx, fn, errs)
+	})
+
+	AfterEach(func() {
+		cancel()
+		signal.Stop(sink)
+	})
+
+	It("calls reload on SIGHUP without canceling", func() {
+		Eventually(sendSignal).Should(BeNumerically(">=", 1))
+		Eventually(sendSignal).Should(BeNumerically(">=", 2))
+		Expect(ctx.Err()).To(BeNil())
+		Expect(errs).NotTo(Receive())
+	})
+
+	It("returns if the context is canceled", func() {
+		cancel()
+		Eventually(errs).Should(Receive(Equal(context.Canceled)))
+	})
+})
+
+var _ = Describe("ReloadOnSignals", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var sink chan os.Signal
+	var counter int64
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		// keep a handler registered so the signal never terminates the test binary
+		sink = make(chan os.Signal, 10)
+		signal.Notify(sink, syscall.SIGHUP, syscall.SIGUSR2)
+		atomic.StoreInt64(&counter, 0)
+		fn := run.ReloadOnSignals(func(ctx context.Context) error {
+			atomic.AddInt64(&counter, 1)
+			return nil
+		})
+		go func(ctx context.Context) {
+			_ = fn(ctx)
+		}(ctx)
+	})
+
+	AfterEach(func() {
+		cancel()
+		signal.Stop(sink)
+	})
+
+	sendSignal := func(sig os.Signal) func() int64 {
+		return func() int64 {
+			process, err := os.FindProcess(os.Getpid())
+			Expect(err).To(BeNil())
+			Expect(process.Signal(sig)).To(BeNil())
+			return atomic.LoadInt64(&counter)
+		}
+	}
+
+	It("reloads on SIGHUP without signals", func() {
+		Eventually(sendSignal(syscall.SIGHUP)).Should(BeNumerically(">=", 1))
+	})
+
+	It("ignores other signals without signals", func() {
+		Expect(sendSignal(syscall.SIGUSR2)()).To(Equal(int64(0)))
+		Eventually(sink).Should(Receive(Equal(syscall.SIGUSR2)))
+		Consistently(func() int64 {
+			return atomic.LoadInt64(&counter)
+		}, 50*time.Millisecond).Should(Equal(int64(0)))
+	})
+})
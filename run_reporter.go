@@ -0,0 +1,212 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+)
+
+// Reporter reports errors to an error tracking service or sink.
+type Reporter interface {
+	Report(ctx context.Context, err error, tags map[string]string)
+}
+
+// ReporterFunc is a function type that implements the Reporter interface.
+type ReporterFunc func(ctx context.Context, err error, tags map[string]string)
+
+func (r ReporterFunc) Report(ctx context.Context, err error, tags map[string]string) {
+	r(ctx, err, tags)
+}
+
+// ErrorFingerprint returns a fingerprint identifying equal errors by their message.
+func ErrorFingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(err.Error()))
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// NewSentryReporter creates a Reporter that captures errors with the given Sentry client.
+// The data of the error is reported as Sentry tags and context like in SkipErrorsAndReport.
+func NewSentryReporter(hasCaptureException HasCaptureException) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error, tags map[string]string) {
+		captureException(ctx, hasCaptureException, err, err, tags)
+	})
+}
+
+// NewFanOutReporter creates a Reporter that reports each error to all given reporters.
+func NewFanOutReporter(reporters ...Reporter) Reporter {
+	return ReporterFunc(func(ctx context.Context, err error, tags map[string]string) {
+		for _, reporter := range reporters {
+			reporter.Report(ctx, err, tags)
+		}
+	})
+}
+
+// JSONLinesReport is a single line written by the JSON-lines reporter.
+type JSONLinesReport struct {
+	Time        time.Time         `json:"time"`
+	Error       string            `json:"error"`
+	Fingerprint string            `json:"fingerprint"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+}
+
+// NewJSONLinesReporter creates a Reporter that writes each error as a JSON object
+// on a separate line to the given writer, e.g. a file opened with os.OpenFile.
+func NewJSONLinesReporter(writer io.Writer) Reporter {
+	return NewJSONLinesReporterWithLogger(nil, writer)
+}
+
+// NewJSONLinesReporterWithLogger creates a Reporter like NewJSONLinesReporter that logs
+// failed writes with the given logger. If logger is nil, the DefaultLogger is used.
+func NewJSONLinesReporterWithLogger(logger Logger, writer io.Writer) Reporter {
+	var mux sync.Mutex
+	return ReporterFunc(func(ctx context.Context, err error, tags map[string]string) {
+		line, marshalErr := json.Marshal(JSONLinesReport{
+			Time:        DefaultClock.Now(),
+			Error:       err.Error(),
+			Fingerprint: ErrorFingerprint(err),
+			Tags:        tags,
+			Data:        errors.DataFromError(err),
+		})
+		if marshalErr != nil {
+			orDefaultLogger(logger).Warn(ctx, "marshal report failed", "error", marshalErr)
+			return
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		if _, writeErr := writer.Write(append(line, '\n')); writeErr != nil {
+			orDefaultLogger(logger).Warn(ctx, "write report failed", "error", writeErr)
+		}
+	})
+}
+
+// Report is an error recorded by the MemoryReporter.
+type Report struct {
+	Err  error
+	Tags map[string]string
+}
+
+// MemoryReporter is a Reporter that keeps all reports in memory, intended for tests.
+type MemoryReporter interface {
+	Reporter
+	// Reports returns all reports in the order they were reported.
+	Reports() []Report
+	// Reset removes all reports.
+	Reset()
+}
+
+// NewMemoryReporter creates a new empty MemoryReporter.
+func NewMemoryReporter() MemoryReporter {
+	return &memoryReporter{}
+}
+
+type memoryReporter struct {
+	mux     sync.Mutex
+	reports []Report
+}
+
+func (m *memoryReporter) Report(ctx context.Context, err error, tags map[string]string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.reports = append(m.reports, Report{Err: err, Tags: tags})
+}
+
+func (m *memoryReporter) Reports() []Report {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	result := make([]Report, len(m.reports))
+	copy(result, m.reports)
+	return result
+}
+
+func (m *memoryReporter) Reset() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.reports = nil
+}
+
+// RateLimitOptions configures NewRateLimitedReporter.
+type RateLimitOptions struct {
+	// Window in which errors with the same fingerprint are reported only once.
+	Window time.Duration
+	// Limit is the maximum number of reports per Window. Zero or less means no limit.
+	Limit int
+	// Fingerprint identifies equal errors. If nil, ErrorFingerprint is used.
+	Fingerprint func(err error) string
+	// Clock provides the time for the windows. If nil, the DefaultClock is used.
+	Clock Clock
+	// Logger is used to log dropped reports. If nil, the DefaultLogger is used.
+	Logger Logger
+}
+
+// NewRateLimitedReporter creates a Reporter that deduplicates errors by fingerprint and limits
+// the number of reports per window, so a flapping job does not flood the error tracker.
+// Dropped reports are logged as debug messages.
+func NewRateLimitedReporter(reporter Reporter, options RateLimitOptions) Reporter {
+	if options.Fingerprint == nil {
+		options.Fingerprint = ErrorFingerprint
+	}
+	return &rateLimitedReporter{
+		reporter: reporter,
+		options:  options,
+		reported: map[string]time.Time{},
+	}
+}
+
+type rateLimitedReporter struct {
+	reporter Reporter
+	options  RateLimitOptions
+
+	mux         sync.Mutex
+	reported    map[string]time.Time
+	windowStart time.Time
+	count       int
+}
+
+func (r *rateLimitedReporter) Report(ctx context.Context, err error, tags map[string]string) {
+	if !r.allow(r.options.Fingerprint(err)) {
+		orDefaultLogger(r.options.Logger).Debug(ctx, "report dropped by rate limit", "error", err)
+		return
+	}
+	r.reporter.Report(ctx, err, tags)
+}
+
+func (r *rateLimitedReporter) allow(fingerprint string) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	now := orDefaultClock(r.options.Clock).Now()
+	for key, reported := range r.reported {
+		if now.Sub(reported) >= r.options.Window {
+			delete(r.reported, key)
+		}
+	}
+	if _, ok := r.reported[fingerprint]; ok {
+		return false
+	}
+	if r.options.Limit > 0 {
+		if now.Sub(r.windowStart) >= r.options.Window {
+			r.windowStart = now
+			r.count = 0
+		}
+		if r.count >= r.options.Limit {
+			return false
+		}
+		r.count++
+	}
+	r.reported[fingerprint] = now
+	return true
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/bborbe/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/mocks"
+)
+
+var _ = Describe("Reporter", func() {
+	var ctx context.Context
+	var memory run.MemoryReporter
+	BeforeEach(func() {
+		ctx = context.Background()
+		memory = run.NewMemoryReporter()
+	})
+	It("records reports in memory", func() {
+		err := stderrors.New("banana")
+		memory.Report(ctx, err, map[string]string{"a": "b"})
+		Expect(memory.Reports()).To(Equal([]run.Report{
+			{Err: err, Tags: map[string]string{"a": "b"}},
+		}))
+		memory.Reset()
+		Expect(memory.Reports()).To(BeEmpty())
+	})
+	It("fans out to all reporters", func() {
+		other := run.NewMemoryReporter()
+		run.NewFanOutReporter(memory, other).Report(ctx, stderrors.New("banana"), nil)
+		Expect(memory.Reports()).To(HaveLen(1))
+		Expect(other.Reports()).To(HaveLen(1))
+	})
+	It("reports to sentry", func() {
+		hasCaptureException := &mocks.HasCaptureException{}
+		run.NewSentryReporter(hasCaptureException).Report(
+			ctx,
+			stderrors.New("banana"),
+			map[string]string{"a": "b"},
+		)
+		Expect(hasCaptureException.CaptureExceptionCallCount()).To(Equal(1))
+		err, hint, _ := hasCaptureException.CaptureExceptionArgsForCall(0)
+		Expect(err.Error()).To(Equal("banana"))
+		Expect(hint.Data).To(Equal(map[string]string{"a": "b"}))
+	})
+	It("writes json lines", func() {
+		buf := &bytes.Buffer{}
+		reporter := run.NewJSONLinesReporter(buf)
+		reporter.Report(
+			ctx,
+			errors.AddDataToError(stderrors.New("banana"), map[string]string{"user": "alice"}),
+			map[string]string{"env": "prod"},
+		)
+		reporter.Report(ctx, stderrors.New("apple"), nil)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		var report run.JSONLinesReport
+		Expect(json.Unmarshal([]byte(lines[0]), &report)).To(Succeed())
+		Expect(report.Error).To(Equal("banana"))
+		Expect(report.Fingerprint).To(Equal(run.ErrorFingerprint(stderrors.New("banana"))))
+		Expect(report.Tags).To(Equal(map[string]string{"env": "prod"}))
+		Expect(report.Data).To(Equal(map[string]string{"user": "alice"}))
+	})
+	It("computes equal fingerprints for equal errors", func() {
+		Expect(run.ErrorFingerprint(stderrors.New("banana"))).To(
+			Equal(run.ErrorFingerprint(stderrors.New("banana"))),
+		)
+		Expect(run.ErrorFingerprint(stderrors.New("banana"))).NotTo(
+			Equal(run.ErrorFingerprint(stderrors.New("apple"))),
+		)
+	})
+	Context("rate limited", func() {
+		var reporter run.Reporter
+		var options run.RateLimitOptions
+		BeforeEach(func() {
+			options = run.RateLimitOptions{
+				Window: time.Hour,
+			}
+		})
+		JustBeforeEach(func() {
+			reporter = run.NewRateLimitedReporter(memory, options)
+		})
+		It("deduplicates errors by fingerprint", func() {
+			for i := 0; i < 10; i++ {
+				reporter.Report(ctx, stderrors.New("banana"), nil)
+			}
+			reporter.Report(ctx, stderrors.New("apple"), nil)
+			Expect(memory.Reports()).To(HaveLen(2))
+		})
+		It("reports again after the window", func() {
+			options.Window = 10 * time.Millisecond
+			reporter = run.NewRateLimitedReporter(memory, options)
+			reporter.Report(ctx, stderrors.New("banana"), nil)
+			time.Sleep(20 * time.Millisecond)
+			reporter.Report(ctx, stderrors.New("banana"), nil)
+			Expect(memory.Reports()).To(HaveLen(2))
+		})
+		Context("with limit", func() {
+			BeforeEach(func() {
+				options.Limit = 2
+			})
+			It("limits the reports per window", func() {
+				reporter.Report(ctx, stderrors.New("banana"), nil)
+				reporter.Report(ctx, stderrors.New("apple"), nil)
+				reporter.Report(ctx, stderrors.New("cherry"), nil)
+				Expect(memory.Reports()).To(HaveLen(2))
+			})
+		})
+		Context("with custom fingerprint", func() {
+			BeforeEach(func() {
+				options.Fingerprint = func(err error) string { return "same" }
+			})
+			It("uses the fingerprint", func() {
+				reporter.Report(ctx, stderrors.New("banana"), nil)
+				reporter.Report(ctx, stderrors.New("apple"), nil)
+				Expect(memory.Reports()).To(HaveLen(1))
+			})
+		})
+	})
+	It("skips errors and reports them", func() {
+		fn := run.SkipErrorsWithReporter(func(ctx context.Context) error {
+			return stderrors.New("banana")
+		}, memory, map[string]string{"a": "b"})
+		Expect(fn(ctx)).To(BeNil())
+		reports := memory.Reports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Err.Error()).To(Equal("run failed: banana"))
+		Expect(reports[0].Tags).To(Equal(map[string]string{"a": "b"}))
+	})
+	It("does not report canceled errors", func() {
+		fn := run.SkipErrorsWithReporter(func(ctx context.Context) error {
+			return context.Canceled
+		}, memory, nil)
+		Expect(fn(ctx)).To(BeNil())
+		Expect(memory.Reports()).To(BeEmpty())
+	})
+})
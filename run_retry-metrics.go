@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RetryMetrics records the behavior of Retry and RetryWaiter.
+type RetryMetrics interface {
+	// Attempt is called before each execution of the function.
+	Attempt()
+	// GiveUp is called when the retry stops because the retries are exhausted or the error is not retryable.
+	GiveUp()
+	// Wait is called with the delay after each completed wait before a retry.
+	// Waits interrupted by the cancellation of the context are not recorded.
+	Wait(delay time.Duration)
+}
+
+// NewRetryMetrics creates RetryMetrics backed by Prometheus counters registered with the given registerer.
+// Already registered equal collectors are reused, so it can be called for many retries.
+func NewRetryMetrics(
+	registerer prometheus.Registerer,
+	namespace string,
+	subsystem string,
+) RetryMetrics {
+	return &retryMetrics{
+		attempts: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retry_attempts_total",
+			Help:      "Total number of attempts",
+		})),
+		giveUps: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retry_give_ups_total",
+			Help:      "Total number of retries that gave up",
+		})),
+		wait: registerOrExisting(registerer, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retry_wait_seconds_total",
+			Help:      "Total time waited between attempts in seconds",
+		})),
+	}
+}
+
+type retryMetrics struct {
+	attempts prometheus.Counter
+	giveUps  prometheus.Counter
+	wait     prometheus.Counter
+}
+
+func (r *retryMetrics) Attempt() {
+	r.attempts.Inc()
+}
+
+func (r *retryMetrics) GiveUp() {
+	r.giveUps.Inc()
+}
+
+func (r *retryMetrics) Wait(delay time.Duration) {
+	r.wait.Add(delay.Seconds())
+}
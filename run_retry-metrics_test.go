@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/mocks"
+)
+
+var _ = Describe("RetryMetrics", func() {
+	var ctx context.Context
+	var registry *prometheus.Registry
+	var waiter *mocks.Waiter
+	var backoff run.Backoff
+	BeforeEach(func() {
+		ctx = context.Background()
+		registry = prometheus.NewRegistry()
+		waiter = &mocks.Waiter{}
+		backoff = run.Backoff{
+			Delay:   time.Second,
+			Retries: 2,
+			Metrics: run.NewRetryMetrics(registry, "ns", "sub"),
+		}
+	})
+	It("records attempts, wait time and give up", func() {
+		fn := run.RetryWaiter(backoff, waiter, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).NotTo(BeNil())
+		Expect(metricValue(registry, "ns_sub_retry_attempts_total", nil)).To(Equal(3.0))
+		Expect(metricValue(registry, "ns_sub_retry_wait_seconds_total", nil)).To(Equal(2.0))
+		Expect(metricValue(registry, "ns_sub_retry_give_ups_total", nil)).To(Equal(1.0))
+	})
+	It("records no wait time for canceled waits", func() {
+		waiter.WaitReturns(context.Canceled)
+		fn := run.RetryWaiter(backoff, waiter, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).NotTo(BeNil())
+		Expect(metricValue(registry, "ns_sub_retry_attempts_total", nil)).To(Equal(1.0))
+		Expect(metricValue(registry, "ns_sub_retry_wait_seconds_total", nil)).To(Equal(0.0))
+	})
+	It("records no give up on success", func() {
+		var counter int
+		fn := run.RetryWaiter(backoff, waiter, func(ctx context.Context) error {
+			counter++
+			if counter == 1 {
+				return stderrors.New("banana")
+			}
+			return nil
+		})
+		Expect(fn(ctx)).To(BeNil())
+		Expect(metricValue(registry, "ns_sub_retry_attempts_total", nil)).To(Equal(2.0))
+		Expect(metricValue(registry, "ns_sub_retry_give_ups_total", nil)).To(Equal(0.0))
+	})
+	It("records give up for not retryable errors", func() {
+		backoff.IsRetryAble = func(err error) bool { return false }
+		fn := run.RetryWaiter(backoff, waiter, func(ctx context.Context) error {
+			return stderrors.New("banana")
+		})
+		Expect(fn(ctx)).NotTo(BeNil())
+		Expect(metricValue(registry, "ns_sub_retry_attempts_total", nil)).To(Equal(1.0))
+		Expect(metricValue(registry, "ns_sub_retry_give_ups_total", nil)).To(Equal(1.0))
+	})
+	It("reuses already registered collectors", func() {
+		Expect(func() { run.NewRetryMetrics(registry, "ns", "sub") }).NotTo(Panic())
+	})
+})
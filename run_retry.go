@@ -25,6 +25,10 @@ type Backoff struct {
 	// IsRetryAble is an optional function that determines if an error is retryable.
 	// If nil, all errors are considered retryable.
 	IsRetryAble func(error) bool `json:"-"`
+	// Metrics is an optional recorder for attempts, give-ups and wait time.
+	Metrics RetryMetrics `json:"-"`
+	// Logger is used to log failed attempts. If nil, the DefaultLogger is used.
+	Logger Logger `json:"-"`
 }
 
 // Retry wraps a function with retry logic using the specified backoff configuration.
@@ -36,6 +40,7 @@ func Retry(backoff Backoff, fn Func) Func {
 // RetryWaiter wraps a function with retry logic using the specified backoff configuration and custom waiter.
 // The waiter controls how delays are implemented, allowing for custom timing behavior.
 func RetryWaiter(backoff Backoff, waiter Waiter, fn Func) Func {
+	name := funcName(fn)
 	return func(ctx context.Context) error {
 		var counter int
 		for {
@@ -43,14 +48,32 @@ func RetryWaiter(backoff Backoff, waiter Waiter, fn Func) Func {
 			case <-ctx.Done():
 				return ctx.Err()
 			default:
+				if backoff.Metrics != nil {
+					backoff.Metrics.Attempt()
+				}
+				start := time.Now()
 				if err := fn(ctx); err != nil {
 					if counter == backoff.Retries {
+						if backoff.Metrics != nil {
+							backoff.Metrics.GiveUp()
+						}
 						return errors.Wrapf(ctx, err, "reached try counter(%d)", backoff.Retries)
 					}
 					if backoff.IsRetryAble != nil && !backoff.IsRetryAble(err) {
+						if backoff.Metrics != nil {
+							backoff.Metrics.GiveUp()
+						}
 						return errors.Wrap(ctx, err, "error is not retryable")
 					}
 					counter++
+					orDefaultLogger(backoff.Logger).Debug(
+						ctx,
+						"attempt failed => retry",
+						"func", name,
+						"attempt", counter,
+						"duration", time.Since(start),
+						"error", err,
+					)
 					if backoff.Delay > 0 {
 						delay := backoff.Delay + backoff.Delay*time.Duration(
 							backoff.Factor*float64(counter-1),
@@ -58,6 +81,9 @@ func RetryWaiter(backoff Backoff, waiter Waiter, fn Func) Func {
 						if err := waiter.Wait(ctx, delay); err != nil {
 							return errors.Wrapf(ctx, err, "wait %v failed", backoff.Delay)
 						}
+						if backoff.Metrics != nil {
+							backoff.Metrics.Wait(delay)
+						}
 					}
 					continue
 				}
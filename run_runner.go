@@ -84,7 +84,7 @@ func All(ctx context.Context, funcs ...Func) error {
 	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	return NewErrorListByChan(onlyNotNil(Run(ctx, funcs...)))
+	return NewErrorListByChan(onlyNotNil(ctx, Run(ctx, funcs...)))
 }
 
 // Sequential executes all given functions one after another in order.
@@ -108,6 +108,7 @@ func Sequential(ctx context.Context, funcs ...Func) (err error) {
 
 // Run executes all given functions in parallel and returns a channel that receives the result of each function.
 // The channel is closed when all functions have completed. This provides the lowest-level access to execution results.
+// If the context was created with ContextWithPanicRecovery, panics are returned as PanicError.
 func Run(ctx context.Context, funcs ...Func) <-chan error {
 	if len(funcs) == 0 {
 		return nil
@@ -116,27 +117,27 @@ func Run(ctx context.Context, funcs ...Func) <-chan error {
 	var wg sync.WaitGroup
 	for _, run := range funcs {
 		wg.Add(1)
-		go func(run Func) {
+		goLabeled(ctx, "Run", func() {
 			defer wg.Done()
-			errors <- run(ctx)
-		}(run)
+			errors <- catchPanicIfEnabled(ctx, run)(ctx)
+		})
 	}
-	go func() {
+	goLabeled(ctx, "Run", func() {
 		wg.Wait()
 		close(errors)
-	}()
+	})
 	return errors
 }
 
-func onlyNotNil(ch <-chan error) <-chan error {
+func onlyNotNil(ctx context.Context, ch <-chan error) <-chan error {
 	errors := make(chan error, runtime.NumCPU())
-	go func() {
+	goLabeled(ctx, "Run", func() {
 		defer close(errors)
 		for err := range ch {
 			if err != nil {
 				errors <- err
 			}
 		}
-	}()
+	})
 	return errors
 }
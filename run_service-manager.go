@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// ServiceManager starts services in order and stops them in reverse order.
+type ServiceManager interface {
+	// Add appends services that are started after all previously added services.
+	Add(services ...Service)
+	// Run starts all services one after another, waiting for each to be ready before starting the next.
+	// Once all services are running it waits until the context is canceled or a service finished,
+	// then stops all started services in reverse order and returns the errors of failed services.
+	// The services are not canceled by the context directly, only by their Stop.
+	Run(ctx context.Context) error
+}
+
+// NewServiceManager creates a new ServiceManager for the given services.
+func NewServiceManager(services ...Service) ServiceManager {
+	return &serviceManager{
+		services: services,
+	}
+}
+
+type serviceManager struct {
+	mux      sync.Mutex
+	services []Service
+}
+
+func (s *serviceManager) Add(services ...Service) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.services = append(s.services, services...)
+}
+
+func (s *serviceManager) Run(ctx context.Context) error {
+	s.mux.Lock()
+	services := make([]Service, len(s.services))
+	copy(services, s.services)
+	s.mux.Unlock()
+
+	// services are stopped explicitly in reverse order and not by the cancellation of the context
+	serviceCtx := context.WithoutCancel(ctx)
+	finished := NewTrigger()
+	var started []Service
+	for _, service := range services {
+		if err := service.Start(serviceCtx); err != nil {
+			return s.result(
+				ctx,
+				started,
+				errors.Wrapf(ctx, err, "start service %s failed", service.Name()),
+			)
+		}
+		started = append(started, service)
+		goLabeled(ctx, "ServiceManager", func() {
+			select {
+			case <-service.Done():
+				finished.Fire()
+			case <-ctx.Done():
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return s.result(ctx, started, ctx.Err())
+		case <-service.Done():
+			// a service that became ready and finished right after is not an error
+			select {
+			case <-service.Ready():
+			default:
+				return s.result(
+					ctx,
+					started,
+					errors.Errorf(ctx, "service %s finished before ready", service.Name()),
+				)
+			}
+			glog.V(2).Infof("service %s ready", service.Name())
+		case <-service.Ready():
+			glog.V(2).Infof("service %s ready", service.Name())
+		}
+	}
+
+	glog.V(2).Infof("all %d services ready", len(services))
+	select {
+	case <-ctx.Done():
+	case <-finished.Done():
+	}
+	return s.result(ctx, started, nil)
+}
+
+// stop stops the given services in reverse order.
+func (s *serviceManager) stop(ctx context.Context, services []Service) {
+	for i := len(services) - 1; i >= 0; i-- {
+		if err := services[i].Stop(ctx); err != nil {
+			glog.Warningf("stop service %s failed: %v", services[i].Name(), err)
+		}
+	}
+}
+
+// result stops the given services and returns the errors of all failed services.
+func (s *serviceManager) result(ctx context.Context, services []Service, err error) error {
+	s.stop(context.WithoutCancel(ctx), services)
+	errs := []error{err}
+	for _, service := range services {
+		if service.State() == ServiceStateFailed {
+			errs = append(errs, errors.Wrapf(ctx, service.Err(), "service %s failed", service.Name()))
+		}
+	}
+	return NewErrorList(errs...)
+}
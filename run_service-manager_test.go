@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("ServiceManager", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var mux sync.Mutex
+	var events []string
+	record := func(event string) {
+		mux.Lock()
+		defer mux.Unlock()
+		events = append(events, event)
+	}
+	recorded := func() []string {
+		mux.Lock()
+		defer mux.Unlock()
+		return append([]string{}, events...)
+	}
+	service := func(name string) run.Service {
+		return run.NewFuncService(name, func(ctx context.Context) error {
+			record("start " + name)
+			<-ctx.Done()
+			record("stop " + name)
+			return nil
+		})
+	}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		mux.Lock()
+		events = nil
+		mux.Unlock()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("starts services in order and stops them in reverse order", func() {
+		manager := run.NewServiceManager(service("a"), service("b"))
+		manager.Add(service("c"))
+		errs := make(chan error, 1)
+		go func() {
+			errs <- manager.Run(ctx)
+		}()
+		Eventually(recorded).Should(HaveLen(3))
+		cancel()
+		Eventually(errs).Should(Receive(BeNil()))
+		Expect(recorded()).To(Equal([]string{
+			"start a", "start b", "start c",
+			"stop c", "stop b", "stop a",
+		}))
+	})
+	It("waits for readiness before starting the next service", func() {
+		ready := make(chan struct{})
+		first := run.NewReadyFuncService("a", func(ctx context.Context, fire run.Fire) error {
+			record("start a")
+			<-ready
+			fire.Fire()
+			<-ctx.Done()
+			return nil
+		})
+		manager := run.NewServiceManager(first, service("b"))
+		errs := make(chan error, 1)
+		go func() {
+			errs <- manager.Run(ctx)
+		}()
+		Eventually(recorded).Should(Equal([]string{"start a"}))
+		Consistently(recorded).Should(Equal([]string{"start a"}))
+		close(ready)
+		Eventually(recorded).Should(Equal([]string{"start a", "start b"}))
+		cancel()
+		Eventually(errs).Should(Receive(BeNil()))
+	})
+	It("stops all services if one fails", func() {
+		failing := make(chan struct{})
+		manager := run.NewServiceManager(
+			service("a"),
+			run.NewFuncService("b", func(ctx context.Context) error {
+				<-failing
+				return stderrors.New("banana")
+			}),
+		)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- manager.Run(ctx)
+		}()
+		Eventually(recorded).Should(Equal([]string{"start a"}))
+		close(failing)
+		var err error
+		Eventually(errs).Should(Receive(&err))
+		Expect(err).To(MatchError(ContainSubstring("service b failed: banana")))
+		Expect(recorded()).To(Equal([]string{"start a", "stop a"}))
+	})
+	It("returns an error if a service finishes before ready", func() {
+		manager := run.NewServiceManager(
+			service("a"),
+			run.NewReadyFuncService("b", func(ctx context.Context, fire run.Fire) error {
+				return nil
+			}),
+			service("c"),
+		)
+		err := manager.Run(ctx)
+		Expect(err).To(MatchError(ContainSubstring("service b finished before ready")))
+		Expect(recorded()).To(Equal([]string{"start a", "stop a"}))
+	})
+	It("does not fail if a service finishes right after ready", func() {
+		for i := 0; i < 20; i++ {
+			manager := run.NewServiceManager(
+				&finishedOnStartService{
+					Service: run.NewFuncService("a", func(ctx context.Context) error {
+						return nil
+					}),
+				},
+				service("b"),
+			)
+			Expect(manager.Run(ctx)).To(Succeed())
+		}
+		Expect(recorded()).To(HaveLen(40))
+	})
+})
+
+// finishedOnStartService returns from Start only after the service is done,
+// so Ready and Done are both closed when the ServiceManager checks them.
+type finishedOnStartService struct {
+	run.Service
+}
+
+func (f *finishedOnStartService) Start(ctx context.Context) error {
+	if err := f.Service.Start(ctx); err != nil {
+		return err
+	}
+	<-f.Service.Done()
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// ServiceState is a state in the lifecycle of a Service.
+type ServiceState int
+
+const (
+	// ServiceStateNew is the state of a service that has not been started.
+	ServiceStateNew ServiceState = iota
+	// ServiceStateStarting is the state of a started service that is not ready yet.
+	ServiceStateStarting
+	// ServiceStateRunning is the state of a ready service.
+	ServiceStateRunning
+	// ServiceStateStopping is the state of a service that has been asked to stop.
+	ServiceStateStopping
+	// ServiceStateStopped is the final state of a service that stopped without error.
+	ServiceStateStopped
+	// ServiceStateFailed is the final state of a service that stopped with an error.
+	ServiceStateFailed
+)
+
+// String returns the name of the state.
+func (s ServiceState) String() string {
+	switch s {
+	case ServiceStateNew:
+		return "New"
+	case ServiceStateStarting:
+		return "Starting"
+	case ServiceStateRunning:
+		return "Running"
+	case ServiceStateStopping:
+		return "Stopping"
+	case ServiceStateStopped:
+		return "Stopped"
+	case ServiceStateFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Service is a long-running component with an observable lifecycle.
+// The Done channel is closed once the service reached a final state (Stopped or Failed).
+type Service interface {
+	Done
+	// Name returns the name of the service.
+	Name() string
+	// Start launches the service in the background. The service runs until Stop is called,
+	// the context is canceled or the service itself finishes.
+	Start(ctx context.Context) error
+	// Ready returns a channel that is closed once the service is ready.
+	Ready() <-chan struct{}
+	// Stop asks the service to stop and waits until it stopped or the context is canceled.
+	Stop(ctx context.Context) error
+	// State returns the current state of the service.
+	State() ServiceState
+	// Err returns the error that caused the Failed state, or nil.
+	Err() error
+	// OnStateChange registers a function called with the new state on every state change.
+	OnStateChange(fn func(state ServiceState))
+}
+
+// ReadyFunc is a function that fires ready once it is able to serve.
+type ReadyFunc func(ctx context.Context, ready Fire) error
+
+// NewFuncService creates a Service that runs the given function and is ready as soon as the function is started.
+func NewFuncService(name string, fn Func) Service {
+	return NewReadyFuncService(name, func(ctx context.Context, ready Fire) error {
+		ready.Fire()
+		return fn(ctx)
+	})
+}
+
+// NewReadyFuncService creates a Service that runs the given function and is ready once the function fires ready.
+// If the function returns nil or context.Canceled after Stop was called, the service is Stopped, otherwise Failed.
+func NewReadyFuncService(name string, fn ReadyFunc) Service {
+	return &funcService{
+		name:  name,
+		fn:    fn,
+		ready: NewTrigger(),
+		done:  NewTrigger(),
+	}
+}
+
+type funcService struct {
+	name  string
+	fn    ReadyFunc
+	ready Trigger
+	done  Trigger
+
+	// notifyMux serializes state changes including the notification of observers
+	notifyMux sync.Mutex
+
+	mux       sync.Mutex
+	state     ServiceState
+	err       error
+	cancel    context.CancelFunc
+	observers []func(state ServiceState)
+}
+
+func (f *funcService) Name() string {
+	return f.name
+}
+
+func (f *funcService) Ready() <-chan struct{} {
+	return f.ready.Done()
+}
+
+func (f *funcService) Done() <-chan struct{} {
+	return f.done.Done()
+}
+
+func (f *funcService) State() ServiceState {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.state
+}
+
+func (f *funcService) Err() error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.err
+}
+
+func (f *funcService) OnStateChange(fn func(state ServiceState)) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.observers = append(f.observers, fn)
+}
+
+func (f *funcService) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	if !f.transition(ServiceStateStarting, nil, func() { f.cancel = cancel }, ServiceStateNew) {
+		cancel()
+		return errors.Errorf(ctx, "start service %s failed: state is %s", f.name, f.State())
+	}
+	glog.V(2).Infof("service %s starting", f.name)
+	goLabeled(ctx, "Service", func() {
+		defer cancel()
+		ready := FireFunc(func() {
+			if f.transition(ServiceStateRunning, nil, nil, ServiceStateStarting) {
+				glog.V(2).Infof("service %s running", f.name)
+				f.ready.Fire()
+			}
+		})
+		err := catchPanicIfEnabled(ctx, func(ctx context.Context) error {
+			return f.fn(ctx, ready)
+		})(ctx)
+		f.finish(err)
+	})
+	return nil
+}
+
+func (f *funcService) finish(err error) {
+	if f.State() == ServiceStateStopping && (err == nil || errors.Is(err, context.Canceled)) {
+		err = nil
+	}
+	if err != nil {
+		glog.Warningf("service %s failed: %v", f.name, err)
+		f.transition(
+			ServiceStateFailed,
+			err,
+			nil,
+			ServiceStateStarting,
+			ServiceStateRunning,
+			ServiceStateStopping,
+		)
+	} else {
+		glog.V(2).Infof("service %s stopped", f.name)
+		f.transition(
+			ServiceStateStopped,
+			nil,
+			nil,
+			ServiceStateStarting,
+			ServiceStateRunning,
+			ServiceStateStopping,
+		)
+	}
+	f.done.Fire()
+}
+
+func (f *funcService) Stop(ctx context.Context) error {
+	var cancel context.CancelFunc
+	if f.transition(ServiceStateStopped, nil, nil, ServiceStateNew) {
+		f.done.Fire()
+		return nil
+	}
+	if f.transition(
+		ServiceStateStopping,
+		nil,
+		func() { cancel = f.cancel },
+		ServiceStateStarting,
+		ServiceStateRunning,
+	) {
+		glog.V(2).Infof("service %s stopping", f.name)
+		cancel()
+	}
+	select {
+	case <-ctx.Done():
+		return errors.Wrapf(ctx, ctx.Err(), "stop service %s failed", f.name)
+	case <-f.done.Done():
+		return nil
+	}
+}
+
+// transition changes the state to the given state if the current state is one of from.
+// The optional update is executed while holding the lock. It returns true if the state changed.
+func (f *funcService) transition(
+	to ServiceState,
+	err error,
+	update func(),
+	from ...ServiceState,
+) bool {
+	f.notifyMux.Lock()
+	defer f.notifyMux.Unlock()
+
+	f.mux.Lock()
+	if !slices.Contains(from, f.state) {
+		f.mux.Unlock()
+		return false
+	}
+	f.state = to
+	f.err = err
+	if update != nil {
+		update()
+	}
+	observers := make([]func(state ServiceState), len(f.observers))
+	copy(observers, f.observers)
+	f.mux.Unlock()
+
+	for _, observer := range observers {
+		observer(to)
+	}
+	return true
+}
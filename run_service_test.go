@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("Service", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var mux sync.Mutex
+	var states []run.ServiceState
+	observe := func(service run.Service) {
+		service.OnStateChange(func(state run.ServiceState) {
+			mux.Lock()
+			defer mux.Unlock()
+			states = append(states, state)
+		})
+	}
+	observed := func() []run.ServiceState {
+		mux.Lock()
+		defer mux.Unlock()
+		return append([]run.ServiceState{}, states...)
+	}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		mux.Lock()
+		states = nil
+		mux.Unlock()
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	It("has a name for each state", func() {
+		Expect(run.ServiceStateNew.String()).To(Equal("New"))
+		Expect(run.ServiceStateStarting.String()).To(Equal("Starting"))
+		Expect(run.ServiceStateRunning.String()).To(Equal("Running"))
+		Expect(run.ServiceStateStopping.String()).To(Equal("Stopping"))
+		Expect(run.ServiceStateStopped.String()).To(Equal("Stopped"))
+		Expect(run.ServiceStateFailed.String()).To(Equal("Failed"))
+		Expect(run.ServiceState(42).String()).To(Equal("Unknown"))
+	})
+	Context("NewFuncService", func() {
+		var service run.Service
+		BeforeEach(func() {
+			service = run.NewFuncService("test", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			observe(service)
+		})
+		It("is new", func() {
+			Expect(service.Name()).To(Equal("test"))
+			Expect(service.State()).To(Equal(run.ServiceStateNew))
+		})
+		It("runs through the whole lifecycle", func() {
+			Expect(service.Start(ctx)).To(BeNil())
+			Eventually(service.Ready()).Should(BeClosed())
+			Expect(service.State()).To(Equal(run.ServiceStateRunning))
+			Expect(service.Stop(ctx)).To(BeNil())
+			Expect(service.State()).To(Equal(run.ServiceStateStopped))
+			Expect(service.Err()).To(BeNil())
+			Expect(service.Done()).To(BeClosed())
+			Expect(observed()).To(Equal([]run.ServiceState{
+				run.ServiceStateStarting,
+				run.ServiceStateRunning,
+				run.ServiceStateStopping,
+				run.ServiceStateStopped,
+			}))
+		})
+		It("can not be started twice", func() {
+			Expect(service.Start(ctx)).To(BeNil())
+			Expect(service.Start(ctx)).NotTo(BeNil())
+		})
+		It("stops without start", func() {
+			Expect(service.Stop(ctx)).To(BeNil())
+			Expect(service.State()).To(Equal(run.ServiceStateStopped))
+			Expect(service.Start(ctx)).NotTo(BeNil())
+		})
+		It("fails if the context is canceled", func() {
+			Expect(service.Start(ctx)).To(BeNil())
+			cancel()
+			Eventually(service.Done()).Should(BeClosed())
+			Expect(service.State()).To(Equal(run.ServiceStateFailed))
+			Expect(service.Err()).To(Equal(context.Canceled))
+		})
+	})
+	Context("NewReadyFuncService", func() {
+		It("is starting until ready fired", func() {
+			ready := make(chan struct{})
+			service := run.NewReadyFuncService("test", func(ctx context.Context, fire run.Fire) error {
+				<-ready
+				fire.Fire()
+				<-ctx.Done()
+				return nil
+			})
+			Expect(service.Start(ctx)).To(BeNil())
+			Consistently(service.Ready()).ShouldNot(BeClosed())
+			Expect(service.State()).To(Equal(run.ServiceStateStarting))
+			close(ready)
+			Eventually(service.Ready()).Should(BeClosed())
+			Expect(service.State()).To(Equal(run.ServiceStateRunning))
+		})
+		It("fails if the function returns an error", func() {
+			service := run.NewReadyFuncService("test", func(ctx context.Context, fire run.Fire) error {
+				return stderrors.New("banana")
+			})
+			observe(service)
+			Expect(service.Start(ctx)).To(BeNil())
+			Eventually(service.Done()).Should(BeClosed())
+			Expect(service.State()).To(Equal(run.ServiceStateFailed))
+			Expect(service.Err()).To(MatchError("banana"))
+			Expect(service.Ready()).NotTo(BeClosed())
+			Expect(observed()).To(Equal([]run.ServiceState{
+				run.ServiceStateStarting,
+				run.ServiceStateFailed,
+			}))
+		})
+	})
+})
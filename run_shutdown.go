@@ -0,0 +1,235 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// DefaultShutdownForceExitCode is the exit code used when the shutdown is forced
+// and ShutdownOptions.ForceExitCode is not set.
+const DefaultShutdownForceExitCode = 2
+
+// ShutdownOptions configures a ShutdownManager.
+type ShutdownOptions struct {
+	// Signals that start the shutdown. A second signal forces the exit.
+	// If empty, SIGINT and SIGTERM are used.
+	Signals []os.Signal
+	// Timeout is the overall deadline for all shutdown hooks, after which the exit is forced.
+	// Zero means no deadline.
+	Timeout time.Duration
+	// ForceExitCode is the exit code used for a forced exit.
+	// If zero, DefaultShutdownForceExitCode is used.
+	ForceExitCode int
+	// Exit terminates the process on a forced exit. If nil, os.Exit is used.
+	Exit func(code int)
+	// Clock provides the timers of the overall Timeout and the hook timeouts.
+	// If nil, the DefaultClock is used.
+	Clock Clock
+}
+
+// ShutdownManager runs registered shutdown hooks after the process received a termination signal.
+// Hooks run phase by phase in ascending order, hooks of the same phase in reverse registration order.
+// The Done channel is closed as soon as the shutdown starts.
+type ShutdownManager interface {
+	Done
+	// Register adds a named hook to phase 0, executed with the given timeout (zero means no timeout).
+	Register(name string, timeout time.Duration, hook Func)
+	// RegisterPhase adds a named hook to the given phase, executed with the given timeout (zero means no timeout).
+	RegisterPhase(phase int, name string, timeout time.Duration, hook Func)
+	// Run waits for a signal or the cancellation of the context and executes all shutdown hooks.
+	// It returns the aggregated errors of the hooks. On a second signal or when the overall timeout
+	// is exceeded, the hooks that hung are logged and the process exits with the force exit code.
+	Run(ctx context.Context) error
+}
+
+// NewShutdownManager creates a new ShutdownManager with the given options.
+func NewShutdownManager(options ShutdownOptions) ShutdownManager {
+	return &shutdownManager{
+		options: options,
+		trigger: NewTrigger(),
+		running: map[int]string{},
+	}
+}
+
+type shutdownHook struct {
+	phase   int
+	index   int
+	name    string
+	timeout time.Duration
+	hook    Func
+}
+
+type shutdownManager struct {
+	options ShutdownOptions
+	trigger Trigger
+
+	mux     sync.Mutex
+	hooks   []shutdownHook
+	running map[int]string
+}
+
+func (s *shutdownManager) Done() <-chan struct{} {
+	return s.trigger.Done()
+}
+
+func (s *shutdownManager) Register(name string, timeout time.Duration, hook Func) {
+	s.RegisterPhase(0, name, timeout, hook)
+}
+
+func (s *shutdownManager) RegisterPhase(phase int, name string, timeout time.Duration, hook Func) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{
+		phase:   phase,
+		index:   len(s.hooks),
+		name:    name,
+		timeout: timeout,
+		hook:    hook,
+	})
+}
+
+func (s *shutdownManager) Run(ctx context.Context) error {
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, s.signals()...)
+	defer signal.Stop(signalCh)
+
+	select {
+	case sig := <-signalCh:
+		glog.V(2).Infof("got signal %s => shutdown", sig)
+	case <-ctx.Done():
+		glog.V(2).Infof("context canceled => shutdown")
+	}
+	s.trigger.Fire()
+
+	var deadline <-chan time.Time
+	if s.options.Timeout > 0 {
+		timer := orDefaultClock(s.options.Clock).NewTimer(s.options.Timeout)
+		defer timer.Stop()
+		deadline = timer.C()
+	}
+
+	done := make(chan error, 1)
+	goLabeled(ctx, "ShutdownManager", func() {
+		done <- s.runHooks(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case err := <-done:
+		return err
+	case sig := <-signalCh:
+		glog.Warningf("got second signal %s => force exit", sig)
+	case <-deadline:
+		glog.Warningf("shutdown timeout %v exceeded => force exit", s.options.Timeout)
+	}
+	glog.Errorf("shutdown hooks hung: %s", strings.Join(s.hung(), ", "))
+	s.exit(s.forceExitCode())
+	return errors.Errorf(ctx, "shutdown forced exit(%d)", s.forceExitCode())
+}
+
+func (s *shutdownManager) runHooks(ctx context.Context) error {
+	s.mux.Lock()
+	hooks := make([]shutdownHook, len(s.hooks))
+	copy(hooks, s.hooks)
+	s.mux.Unlock()
+
+	sort.Slice(hooks, func(i, j int) bool {
+		if hooks[i].phase != hooks[j].phase {
+			return hooks[i].phase < hooks[j].phase
+		}
+		return hooks[i].index > hooks[j].index
+	})
+
+	var errs []error
+	for _, hook := range hooks {
+		if err := s.runHook(ctx, hook); err != nil {
+			errs = append(errs, errors.Wrapf(ctx, err, "shutdown hook %s failed", hook.name))
+		}
+	}
+	return NewErrorList(errs...)
+}
+
+func (s *shutdownManager) runHook(ctx context.Context, hook shutdownHook) error {
+	// the deadline uses the timer of the clock, so a FakeClock controls hook timeouts as well
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var deadline <-chan time.Time
+	if hook.timeout > 0 {
+		timer := orDefaultClock(s.options.Clock).NewTimer(hook.timeout)
+		defer timer.Stop()
+		deadline = timer.C()
+	}
+
+	s.mux.Lock()
+	s.running[hook.index] = hook.name
+	s.mux.Unlock()
+
+	glog.V(2).Infof("run shutdown hook %s", hook.name)
+	done := make(chan error, 1)
+	goLabeled(ctx, "ShutdownManager", func() {
+		done <- catchPanicIfEnabled(ctx, hook.hook)(ctx)
+		s.mux.Lock()
+		delete(s.running, hook.index)
+		s.mux.Unlock()
+	})
+
+	select {
+	case err := <-done:
+		glog.V(2).Infof("shutdown hook %s completed", hook.name)
+		return err
+	case <-deadline:
+		glog.Warningf("shutdown hook %s timed out after %v", hook.name, hook.timeout)
+		cancel(context.DeadlineExceeded)
+		return context.DeadlineExceeded
+	}
+}
+
+// hung returns the names of all hooks that have been started but not completed.
+func (s *shutdownManager) hung() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	indexes := make([]int, 0, len(s.running))
+	for index := range s.running {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	result := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		result = append(result, s.running[index])
+	}
+	return result
+}
+
+func (s *shutdownManager) signals() []os.Signal {
+	if len(s.options.Signals) > 0 {
+		return s.options.Signals
+	}
+	return []os.Signal{os.Interrupt, syscall.SIGTERM}
+}
+
+func (s *shutdownManager) forceExitCode() int {
+	if s.options.ForceExitCode != 0 {
+		return s.options.ForceExitCode
+	}
+	return DefaultShutdownForceExitCode
+}
+
+func (s *shutdownManager) exit(code int) {
+	if s.options.Exit != nil {
+		s.options.Exit(code)
+		return
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("ShutdownManager", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var sink chan os.Signal
+	var release chan struct{}
+	var exitCodes chan int
+	var options run.ShutdownOptions
+	var manager run.ShutdownManager
+	var mux sync.Mutex
+	var order []string
+	hook := func(name string, err error) run.Func {
+		return func(ctx context.Context) error {
+			mux.Lock()
+			defer mux.Unlock()
+			order = append(order, name)
+			return err
+		}
+	}
+	hang := func(started chan struct{}) run.Func {
+		release := release
+		return func(ctx context.Context) error {
+			if started != nil {
+				close(started)
+			}
+			<-release
+			return nil
+		}
+	}
+	sendSignal := func() {
+		process, err := os.FindProcess(os.Getpid())
+		Expect(err).To(BeNil())
+		Expect(process.Signal(syscall.SIGUSR2)).To(BeNil())
+	}
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		// keep a handler registered so the signal never terminates the test binary
+		sink = make(chan os.Signal, 10)
+		signal.Notify(sink, syscall.SIGUSR2)
+		release = make(chan struct{})
+		exitCodes = make(chan int, 1)
+		order = nil
+		options = run.ShutdownOptions{
+			Signals: []os.Signal{syscall.SIGUSR2},
+			Exit: func(code int) {
+				exitCodes <- code
+			},
+		}
+	})
+	AfterEach(func() {
+		cancel()
+		close(release)
+		signal.Stop(sink)
+	})
+	JustBeforeEach(func() {
+		manager = run.NewShutdownManager(options)
+	})
+	It("runs hooks in reverse registration order after cancel", func() {
+		manager.Register("first", time.Second, hook("first", nil))
+		manager.Register("second", time.Second, hook("second", nil))
+		manager.Register("third", time.Second, hook("third", nil))
+		cancel()
+		Expect(manager.Run(ctx)).To(BeNil())
+		Expect(order).To(Equal([]string{"third", "second", "first"}))
+		Expect(manager.Done()).To(BeClosed())
+	})
+	It("runs phases in ascending order", func() {
+		manager.RegisterPhase(2, "db", 0, hook("db", nil))
+		manager.RegisterPhase(1, "http", 0, hook("http", nil))
+		manager.RegisterPhase(1, "grpc", 0, hook("grpc", nil))
+		cancel()
+		Expect(manager.Run(ctx)).To(BeNil())
+		Expect(order).To(Equal([]string{"grpc", "http", "db"}))
+	})
+	It("starts the shutdown on signal", func() {
+		manager.Register("hook", time.Second, hook("hook", nil))
+		errs := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			errs <- manager.Run(ctx)
+		}()
+		Consistently(manager.Done(), 50*time.Millisecond).ShouldNot(BeClosed())
+		sendSignal()
+		Eventually(errs).Should(Receive(BeNil()))
+		Expect(order).To(Equal([]string{"hook"}))
+	})
+	It("returns errors of all hooks", func() {
+		manager.Register("first", time.Second, hook("first", stderrors.New("banana")))
+		manager.Register("second", time.Second, hook("second", stderrors.New("apple")))
+		cancel()
+		err := manager.Run(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("shutdown hook first failed: banana"))
+		Expect(err.Error()).To(ContainSubstring("shutdown hook second failed: apple"))
+	})
+	It("continues with the next hook after a hook timed out", func() {
+		manager.Register("fast", time.Second, hook("fast", nil))
+		manager.Register("slow", 10*time.Millisecond, hang(nil))
+		cancel()
+		err := manager.Run(ctx)
+		Expect(err).NotTo(BeNil())
+		Expect(stderrors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(order).To(Equal([]string{"fast"}))
+		Expect(exitCodes).NotTo(Receive())
+	})
+	Context("with timeout", func() {
+		BeforeEach(func() {
+			options.Timeout = 50 * time.Millisecond
+		})
+		It("forces the exit if hooks hang", func() {
+			manager.Register("hang", 0, hang(nil))
+			cancel()
+			Expect(manager.Run(ctx)).NotTo(BeNil())
+			Expect(exitCodes).To(Receive(Equal(run.DefaultShutdownForceExitCode)))
+		})
+	})
+	Context("with force exit code", func() {
+		BeforeEach(func() {
+			options.ForceExitCode = 42
+		})
+		It("forces the exit on a second signal", func() {
+			started := make(chan struct{})
+			manager.Register("hang", 0, hang(started))
+			errs := make(chan error, 1)
+			go func() {
+				defer GinkgoRecover()
+				errs <- manager.Run(ctx)
+			}()
+			Eventually(func() error {
+				select {
+				case <-started:
+					return nil
+				default:
+					sendSignal()
+					return stderrors.New("not started")
+				}
+			}).Should(BeNil())
+			Consistently(exitCodes, 50*time.Millisecond).ShouldNot(Receive())
+			sendSignal()
+			Eventually(exitCodes).Should(Receive(Equal(42)))
+			Eventually(errs).Should(Receive(HaveOccurred()))
+		})
+	})
+})
@@ -6,18 +6,27 @@ package run
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/bborbe/errors"
 	"github.com/getsentry/sentry-go"
-	"github.com/golang/glog"
 )
 
 // SkipErrors wraps the given function to suppress all errors and always return nil.
 // Errors are logged as warnings but do not propagate to the caller.
 func SkipErrors(fn Func) Func {
+	return SkipErrorsWithLogger(nil, fn)
+}
+
+// SkipErrorsWithLogger wraps the given function like SkipErrors and logs errors with the given logger.
+// If logger is nil, the DefaultLogger is used.
+func SkipErrorsWithLogger(logger Logger, fn Func) Func {
+	name := funcName(fn)
 	return func(ctx context.Context) error {
+		start := time.Now()
 		if err := fn(ctx); err != nil {
-			glog.Warningf("run failed: %v", err)
+			orDefaultLogger(logger).Warn(ctx, "run failed", runFailedArgs(name, time.Since(start), err)...)
 		}
 		return nil
 	}
@@ -37,24 +46,99 @@ type HasCaptureException interface {
 
 // SkipErrorsAndReport wraps the given function to suppress all errors, report them to an error tracking service, and always return nil.
 // Context cancellation errors are not reported. The function logs errors as warnings and sends them to the specified error tracker.
+// The data attached to the error with github.com/bborbe/errors is logged as attributes and reported as Sentry tags and context.
 func SkipErrorsAndReport(
 	fn Func,
 	hasCaptureException HasCaptureException,
 	tags map[string]string,
 ) Func {
+	return SkipErrorsAndReportWithLogger(nil, fn, hasCaptureException, tags)
+}
+
+// SkipErrorsAndReportWithLogger wraps the given function like SkipErrorsAndReport and logs errors with the given logger.
+// If logger is nil, the DefaultLogger is used.
+func SkipErrorsAndReportWithLogger(
+	logger Logger,
+	fn Func,
+	hasCaptureException HasCaptureException,
+	tags map[string]string,
+) Func {
+	name := funcName(fn)
+	return func(ctx context.Context) error {
+		start := time.Now()
+		if err := fn(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			orDefaultLogger(logger).Warn(ctx, "run failed", runFailedArgs(name, time.Since(start), err)...)
+			captureException(ctx, hasCaptureException, errors.Wrapf(ctx, err, "run failed"), err, tags)
+		}
+		return nil
+	}
+}
+
+// SkipErrorsWithReporter wraps the given function to suppress all errors, report them to the given Reporter, and always return nil.
+// Context cancellation errors are not reported. Errors are logged as warnings with the DefaultLogger.
+func SkipErrorsWithReporter(fn Func, reporter Reporter, tags map[string]string) Func {
+	return SkipErrorsWithReporterWithLogger(nil, fn, reporter, tags)
+}
+
+// SkipErrorsWithReporterWithLogger wraps the given function like SkipErrorsWithReporter and logs errors with the given logger.
+// If logger is nil, the DefaultLogger is used.
+func SkipErrorsWithReporterWithLogger(
+	logger Logger,
+	fn Func,
+	reporter Reporter,
+	tags map[string]string,
+) Func {
+	name := funcName(fn)
 	return func(ctx context.Context) error {
+		start := time.Now()
 		if err := fn(ctx); err != nil && !errors.Is(err, context.Canceled) {
-			glog.Warningf("run failed: %v", err)
-			hasCaptureException.CaptureException(
-				errors.Wrapf(ctx, err, "run failed"),
-				&sentry.EventHint{
-					Context:           ctx,
-					Data:              tags,
-					OriginalException: err,
-				},
-				sentry.NewScope(),
-			)
+			orDefaultLogger(logger).Warn(ctx, "run failed", runFailedArgs(name, time.Since(start), err)...)
+			reporter.Report(ctx, errors.Wrapf(ctx, err, "run failed"), tags)
 		}
 		return nil
 	}
 }
+
+// captureException reports the exception with the given tags. The data attached to the original
+// error with github.com/bborbe/errors is added as Sentry context "data" and merged into the tags,
+// tags given by the caller take precedence over error data with the same key.
+// The value and stack of a PanicError are added as Sentry context "panic".
+func captureException(
+	ctx context.Context,
+	hasCaptureException HasCaptureException,
+	exception error,
+	original error,
+	tags map[string]string,
+) {
+	data := errors.DataFromError(original)
+	mergedTags := make(map[string]string, len(data)+len(tags))
+	extras := make(sentry.Context, len(data))
+	for key, value := range data {
+		mergedTags[key] = value
+		extras[key] = value
+	}
+	for key, value := range tags {
+		mergedTags[key] = value
+	}
+	scope := sentry.NewScope()
+	scope.SetTags(mergedTags)
+	if len(extras) > 0 {
+		scope.SetContext("data", extras)
+	}
+	var panicErr *PanicError
+	if errors.As(exception, &panicErr) {
+		scope.SetContext("panic", sentry.Context{
+			"value": fmt.Sprintf("%v", panicErr.Value),
+			"stack": string(panicErr.Stack),
+		})
+	}
+	hasCaptureException.CaptureException(
+		exception,
+		&sentry.EventHint{
+			Context:           ctx,
+			Data:              mergedTags,
+			OriginalException: original,
+		},
+		scope,
+	)
+}
@@ -9,6 +9,7 @@ import (
 	stderrors "errors"
 
 	"github.com/bborbe/errors"
+	"github.com/getsentry/sentry-go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -90,6 +91,32 @@ var _ = Describe("SkipErrorsAndReport", func() {
 			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
 		})
 	})
+	Context("with error data", func() {
+		BeforeEach(func() {
+			fn := run.SkipErrorsAndReport(
+				func(ctx context.Context) error {
+					return errors.AddDataToError(
+						stderrors.New("banana"),
+						map[string]string{"user": "alice", "env": "error"},
+					)
+				},
+				sentryClient,
+				map[string]string{"env": "prod"},
+			)
+			err = fn(ctx)
+		})
+		It("reports the error data merged with the tags", func() {
+			Expect(sentryClient.CaptureExceptionCallCount()).To(Equal(1))
+			_, hint, scope := sentryClient.CaptureExceptionArgsForCall(0)
+			Expect(hint.Data).To(Equal(map[string]string{"user": "alice", "env": "prod"}))
+			event := scope.ApplyToEvent(&sentry.Event{}, hint, nil)
+			Expect(event.Tags).To(Equal(map[string]string{"user": "alice", "env": "prod"}))
+			Expect(event.Contexts).To(HaveKeyWithValue("data", sentry.Context{
+				"user": "alice",
+				"env":  "error",
+			}))
+		})
+	})
 	Context("with context canceled error", func() {
 		BeforeEach(func() {
 			fn := run.SkipErrorsAndReport(
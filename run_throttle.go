@@ -0,0 +1,27 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"time"
+)
+
+// Throttle wraps a function so that it executes at most once per interval.
+// The first call executes immediately, further calls within the interval collapse into one trailing execution.
+// It uses the DefaultWaiter for delays.
+func Throttle(interval time.Duration, fn Func) Func {
+	return ThrottleWaiter(interval, DefaultWaiter, fn)
+}
+
+// ThrottleWaiter wraps a function so that it executes at most once per interval using the given waiter.
+// It behaves like DebounceWaiter with leading and trailing executions and a MaxWait equal to the interval.
+func ThrottleWaiter(interval time.Duration, waiter Waiter, fn Func) Func {
+	return DebounceWaiter(DebounceOptions{
+		Wait:     interval,
+		MaxWait:  interval,
+		Leading:  true,
+		Trailing: true,
+	}, waiter, fn)
+}
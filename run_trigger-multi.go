@@ -5,6 +5,7 @@
 package run
 
 import (
+	"context"
 	"sync"
 )
 
@@ -16,58 +17,141 @@ type AddFire interface {
 
 // MultiTrigger manages multiple triggers and fires when all of them have been triggered.
 // It combines the ability to add new triggers dynamically and wait for all triggers to fire.
+//
+// Done returns a channel shared by all callers: a channel obtained while triggers are pending
+// also waits for triggers added later. Once all triggers fired the channel stays closed,
+// and a trigger added afterwards starts a new Done channel.
 type MultiTrigger interface {
 	Done
 	AddFire
 }
 
+// MultiTriggerWaiter is a MultiTrigger that additionally supports "any" semantics,
+// removal of triggers and context-aware waiting.
+type MultiTriggerWaiter interface {
+	MultiTrigger
+	// Any returns a channel that is closed as soon as the first trigger of the group fired.
+	Any() <-chan struct{}
+	// Remove removes a trigger created by Add from the group.
+	// If all remaining triggers fired, the Done channel is closed.
+	Remove(trigger Trigger)
+	// Wait waits until all triggers fired or the context is canceled.
+	Wait(ctx context.Context) error
+}
+
 // NewMultiTrigger creates a new MultiTrigger that waits for all added triggers to fire.
 // The Done channel signals when all individual triggers have been fired.
+// Triggers notify the group when they fire, so no goroutines are spawned for waiting.
 func NewMultiTrigger() MultiTrigger {
-	return &multiTrigger{}
+	return NewMultiTriggerWaiter()
+}
+
+// NewMultiTriggerWaiter creates a new MultiTriggerWaiter that waits for all added triggers to fire.
+func NewMultiTriggerWaiter() MultiTriggerWaiter {
+	all := make(chan struct{})
+	close(all)
+	return &multiTrigger{
+		members: map[*multiTriggerMember]bool{},
+		all:     all,
+		any:     make(chan struct{}),
+	}
 }
 
 type multiTrigger struct {
-	mux      sync.Mutex
-	triggers []Trigger
+	mux sync.Mutex
+	// members maps each trigger of the group to whether it fired
+	members  map[*multiTriggerMember]bool
+	pending  int
+	all      chan struct{}
+	any      chan struct{}
+	anyFired bool
 }
 
 func (m *multiTrigger) Add() Trigger {
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
-	result := NewTrigger()
-	m.triggers = append(m.triggers, result)
+	result := &multiTriggerMember{
+		Trigger: NewTrigger(),
+		parent:  m,
+	}
+	m.members[result] = false
+	if m.pending == 0 {
+		m.all = make(chan struct{})
+	}
+	m.pending++
 	return result
 }
 
-func (m *multiTrigger) Done() <-chan struct{} {
+func (m *multiTrigger) Remove(trigger Trigger) {
+	member, ok := trigger.(*multiTriggerMember)
+	if !ok {
+		return
+	}
+
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
-	result := NewTrigger()
-	var wg sync.WaitGroup
-	counter := 0
-	for _, trigger := range m.triggers {
-		v := trigger
-		select {
-		case <-v.Done():
-			counter++
-		default:
-			wg.Add(1)
-			go func(done Done) {
-				<-done.Done()
-				wg.Done()
-			}(v)
+	fired, ok := m.members[member]
+	if !ok {
+		return
+	}
+	delete(m.members, member)
+	if !fired {
+		m.pending--
+		if m.pending == 0 {
+			close(m.all)
 		}
 	}
-	if counter == len(m.triggers) {
-		result.Fire()
-	} else {
-		go func() {
-			wg.Wait()
-			result.Fire()
-		}()
+}
+
+func (m *multiTrigger) Done() <-chan struct{} {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.all
+}
+
+func (m *multiTrigger) Any() <-chan struct{} {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.any
+}
+
+func (m *multiTrigger) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.Done():
+		return nil
+	}
+}
+
+// fired marks the member as fired and closes the channels whose condition is reached.
+func (m *multiTrigger) fired(member *multiTriggerMember) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	fired, ok := m.members[member]
+	if !ok || fired {
+		return
+	}
+	m.members[member] = true
+	if !m.anyFired {
+		m.anyFired = true
+		close(m.any)
+	}
+	m.pending--
+	if m.pending == 0 {
+		close(m.all)
 	}
-	return result.Done()
+}
+
+type multiTriggerMember struct {
+	Trigger
+	parent *multiTrigger
+}
+
+func (m *multiTriggerMember) Fire() {
+	m.Trigger.Fire()
+	m.parent.fired(m)
 }
@@ -5,6 +5,9 @@
 package run_test
 
 import (
+	"context"
+	"runtime"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -58,4 +61,84 @@ var _ = Describe("MultiTrigger", func() {
 			Fail("should be done")
 		}
 	})
+	It("waits with an earlier obtained Done for triggers added later", func() {
+		done := multiTrigger.Done()
+		t4 := multiTrigger.Add()
+		t1.Fire()
+		t2.Fire()
+		t3.Fire()
+		Expect(done).NotTo(BeClosed())
+		t4.Fire()
+		Expect(done).To(BeClosed())
+	})
+})
+
+var _ = Describe("MultiTriggerWaiter", func() {
+	var multiTrigger run.MultiTriggerWaiter
+	var t1, t2, t3 run.Trigger
+	BeforeEach(func() {
+		multiTrigger = run.NewMultiTriggerWaiter()
+		t1 = multiTrigger.Add()
+		t2 = multiTrigger.Add()
+		t3 = multiTrigger.Add()
+	})
+	It("is done without triggers", func() {
+		Expect(run.NewMultiTriggerWaiter().Done()).To(BeClosed())
+	})
+	It("fires any on the first trigger", func() {
+		Expect(multiTrigger.Any()).NotTo(BeClosed())
+		t2.Fire()
+		Expect(multiTrigger.Any()).To(BeClosed())
+		Expect(multiTrigger.Done()).NotTo(BeClosed())
+	})
+	It("is done if the pending triggers are removed", func() {
+		t1.Fire()
+		multiTrigger.Remove(t2)
+		Expect(multiTrigger.Done()).NotTo(BeClosed())
+		multiTrigger.Remove(t3)
+		Expect(multiTrigger.Done()).To(BeClosed())
+		Expect(multiTrigger.Any()).To(BeClosed())
+	})
+	It("ignores fires of removed triggers", func() {
+		multiTrigger.Remove(t1)
+		t1.Fire()
+		Expect(t1.Done()).To(BeClosed())
+		Expect(multiTrigger.Any()).NotTo(BeClosed())
+	})
+	It("ignores unknown triggers on remove", func() {
+		multiTrigger.Remove(run.NewTrigger())
+		multiTrigger.Remove(run.NewMultiTrigger().Add())
+		t1.Fire()
+		t2.Fire()
+		Expect(multiTrigger.Done()).NotTo(BeClosed())
+	})
+	It("is not done after adding a trigger to a done group", func() {
+		t1.Fire()
+		t2.Fire()
+		t3.Fire()
+		done := multiTrigger.Done()
+		t4 := multiTrigger.Add()
+		Expect(done).To(BeClosed())
+		Expect(multiTrigger.Done()).NotTo(BeClosed())
+		t4.Fire()
+		Expect(multiTrigger.Done()).To(BeClosed())
+	})
+	It("does not spawn goroutines on Done", func() {
+		before := runtime.NumGoroutine()
+		for i := 0; i < 100; i++ {
+			multiTrigger.Done()
+		}
+		Expect(runtime.NumGoroutine()).To(BeNumerically("<=", before))
+	})
+	It("waits until all triggers fired", func() {
+		t1.Fire()
+		t2.Fire()
+		t3.Fire()
+		Expect(multiTrigger.Wait(context.Background())).To(Succeed())
+	})
+	It("stops waiting if the context is canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(multiTrigger.Wait(ctx)).To(MatchError(context.Canceled))
+	})
 })
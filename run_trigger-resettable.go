@@ -0,0 +1,98 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// ResettableTrigger is a Trigger that can be re-armed with Reset and counts its fires in generations.
+type ResettableTrigger interface {
+	Trigger
+	// Reset re-arms a fired trigger, the Done channel returned afterwards is open until the next Fire.
+	// Channels returned before stay closed.
+	Reset()
+	// Generation returns the number of fires so far, every Fire starts a new generation.
+	Generation() uint64
+	// Next returns a channel that is closed on the next Fire, regardless of Reset.
+	Next() <-chan struct{}
+	// WaitGeneration waits for the first Fire after the given generation and returns the current generation.
+	// It returns immediately if the trigger already fired after the generation.
+	WaitGeneration(ctx context.Context, generation uint64) (uint64, error)
+}
+
+// NewResettableTrigger creates a new ResettableTrigger in unfired state with generation 0.
+func NewResettableTrigger() ResettableTrigger {
+	return &resettableTrigger{
+		done: make(chan struct{}),
+		next: make(chan struct{}),
+	}
+}
+
+type resettableTrigger struct {
+	mux        sync.Mutex
+	generation uint64
+	fired      bool
+	done       chan struct{}
+	next       chan struct{}
+}
+
+func (t *resettableTrigger) Fire() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.generation++
+	if !t.fired {
+		t.fired = true
+		close(t.done)
+	}
+	close(t.next)
+	t.next = make(chan struct{})
+}
+
+func (t *resettableTrigger) Done() <-chan struct{} {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.done
+}
+
+func (t *resettableTrigger) Reset() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.fired {
+		t.fired = false
+		t.done = make(chan struct{})
+	}
+}
+
+func (t *resettableTrigger) Generation() uint64 {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.generation
+}
+
+func (t *resettableTrigger) Next() <-chan struct{} {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.next
+}
+
+func (t *resettableTrigger) WaitGeneration(ctx context.Context, generation uint64) (uint64, error) {
+	for {
+		t.mux.Lock()
+		current := t.generation
+		next := t.next
+		t.mux.Unlock()
+
+		if current > generation {
+			return current, nil
+		}
+		select {
+		case <-ctx.Done():
+			return current, ctx.Err()
+		case <-next:
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/mocks"
+)
+
+var _ = Describe("ResettableTrigger", func() {
+	var ctx context.Context
+	var trigger run.ResettableTrigger
+	BeforeEach(func() {
+		ctx = context.Background()
+		trigger = run.NewResettableTrigger()
+	})
+	It("starts unfired with generation 0", func() {
+		Expect(trigger.Done()).NotTo(BeClosed())
+		Expect(trigger.Generation()).To(Equal(uint64(0)))
+	})
+	It("counts every fire as generation", func() {
+		trigger.Fire()
+		trigger.Fire()
+		Expect(trigger.Done()).To(BeClosed())
+		Expect(trigger.Generation()).To(Equal(uint64(2)))
+	})
+	It("re-arms with reset", func() {
+		trigger.Fire()
+		fired := trigger.Done()
+		trigger.Reset()
+		Expect(fired).To(BeClosed())
+		Expect(trigger.Done()).NotTo(BeClosed())
+		Expect(trigger.Generation()).To(Equal(uint64(1)))
+
+		trigger.Fire()
+		Expect(trigger.Done()).To(BeClosed())
+		Expect(trigger.Generation()).To(Equal(uint64(2)))
+	})
+	It("closes next on the next fire even if already fired", func() {
+		trigger.Fire()
+		next := trigger.Next()
+		Expect(next).NotTo(BeClosed())
+		trigger.Fire()
+		Expect(next).To(BeClosed())
+		Expect(trigger.Next()).NotTo(BeClosed())
+	})
+	It("returns immediately if fired after the generation", func() {
+		trigger.Fire()
+		generation, err := trigger.WaitGeneration(ctx, 0)
+		Expect(err).To(BeNil())
+		Expect(generation).To(Equal(uint64(1)))
+	})
+	It("waits for the next fire after the generation", func() {
+		trigger.Fire()
+		result := make(chan uint64, 1)
+		go func() {
+			generation, _ := trigger.WaitGeneration(ctx, 2)
+			result <- generation
+		}()
+		trigger.Fire()
+		Consistently(result, 50*time.Millisecond).ShouldNot(Receive())
+		trigger.Fire()
+		Eventually(result).Should(Receive(Equal(uint64(3))))
+	})
+	It("returns error if the context is canceled", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		generation, err := trigger.WaitGeneration(ctx, 0)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(generation).To(Equal(uint64(0)))
+	})
+})
+
+var _ = Describe("TriggeredLoop", func() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var trigger run.ResettableTrigger
+	var runnable *mocks.Runnable
+	var errs chan error
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		trigger = run.NewResettableTrigger()
+		runnable = &mocks.Runnable{}
+		errs = make(chan error, 1)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	JustBeforeEach(func() {
+		ctx, errs := ctx, errs
+		fn := run.TriggeredLoop(runnable.Run, trigger)
+		go func() {
+			errs <- fn(ctx)
+		}()
+	})
+	It("runs on every fire until the context is canceled", func() {
+		Consistently(runnable.RunCallCount, 50*time.Millisecond).Should(Equal(0))
+		trigger.Fire()
+		Eventually(runnable.RunCallCount).Should(Equal(1))
+		Eventually(trigger.Done).ShouldNot(BeClosed())
+		trigger.Fire()
+		Eventually(runnable.RunCallCount).Should(Equal(2))
+
+		cancel()
+		Eventually(errs).Should(Receive(MatchError(context.Canceled)))
+	})
+	Context("already fired", func() {
+		BeforeEach(func() {
+			trigger.Fire()
+		})
+		It("runs immediately", func() {
+			Eventually(runnable.RunCallCount).Should(Equal(1))
+			Consistently(runnable.RunCallCount, 50*time.Millisecond).Should(Equal(1))
+		})
+	})
+	Context("fn fails", func() {
+		BeforeEach(func() {
+			runnable.RunReturns(errors.New("banana"))
+			trigger.Fire()
+		})
+		It("returns the error", func() {
+			Eventually(errs).Should(Receive(MatchError("banana")))
+		})
+	})
+})
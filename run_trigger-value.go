@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// ValueTrigger is a trigger that fires once with a value or an error, like a promise.
+// The first Fire or FireErr wins, later calls are ignored.
+type ValueTrigger[T any] interface {
+	Done
+	// Fire sets the value and closes the Done channel.
+	Fire(value T)
+	// FireErr sets the error and closes the Done channel.
+	FireErr(err error)
+	// Wait waits until the trigger fired and returns its value and error,
+	// or the error of the context if it is canceled before.
+	Wait(ctx context.Context) (T, error)
+}
+
+// NewValueTrigger creates a new ValueTrigger in unfired state.
+func NewValueTrigger[T any]() ValueTrigger[T] {
+	return NewValueTriggerWithTrigger[T](NewTrigger())
+}
+
+// NewValueTriggerWithTrigger creates a new ValueTrigger that fires the given trigger,
+// e.g. one created by MultiTrigger.Add to include the value in a group.
+func NewValueTriggerWithTrigger[T any](trigger Trigger) ValueTrigger[T] {
+	return &valueTrigger[T]{
+		trigger: trigger,
+	}
+}
+
+type valueTrigger[T any] struct {
+	trigger Trigger
+
+	mux   sync.Mutex
+	fired bool
+	value T
+	err   error
+}
+
+func (v *valueTrigger[T]) Fire(value T) {
+	v.fire(value, nil)
+}
+
+func (v *valueTrigger[T]) FireErr(err error) {
+	var empty T
+	v.fire(empty, err)
+}
+
+func (v *valueTrigger[T]) fire(value T, err error) {
+	v.mux.Lock()
+	if v.fired {
+		v.mux.Unlock()
+		return
+	}
+	v.fired = true
+	v.value = value
+	v.err = err
+	v.mux.Unlock()
+
+	v.trigger.Fire()
+}
+
+func (v *valueTrigger[T]) Done() <-chan struct{} {
+	return v.trigger.Done()
+}
+
+func (v *valueTrigger[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-ctx.Done():
+		var empty T
+		return empty, ctx.Err()
+	case <-v.trigger.Done():
+		v.mux.Lock()
+		defer v.mux.Unlock()
+		return v.value, v.err
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("ValueTrigger", func() {
+	var ctx context.Context
+	var trigger run.ValueTrigger[string]
+	BeforeEach(func() {
+		ctx = context.Background()
+		trigger = run.NewValueTrigger[string]()
+	})
+	It("is not done before fire", func() {
+		Expect(trigger.Done()).NotTo(BeClosed())
+	})
+	It("returns the fired value", func() {
+		trigger.Fire("127.0.0.1:8080")
+		Expect(trigger.Done()).To(BeClosed())
+		value, err := trigger.Wait(ctx)
+		Expect(err).To(BeNil())
+		Expect(value).To(Equal("127.0.0.1:8080"))
+	})
+	It("returns the fired error", func() {
+		trigger.FireErr(errors.New("banana"))
+		value, err := trigger.Wait(ctx)
+		Expect(err).To(MatchError("banana"))
+		Expect(value).To(BeEmpty())
+	})
+	It("keeps the first fire", func() {
+		trigger.Fire("first")
+		trigger.Fire("second")
+		trigger.FireErr(errors.New("banana"))
+		value, err := trigger.Wait(ctx)
+		Expect(err).To(BeNil())
+		Expect(value).To(Equal("first"))
+	})
+	It("waits for the fire", func() {
+		result := make(chan string, 1)
+		go func() {
+			value, _ := trigger.Wait(ctx)
+			result <- value
+		}()
+		Consistently(result, 50*time.Millisecond).ShouldNot(Receive())
+		trigger.Fire("banana")
+		Eventually(result).Should(Receive(Equal("banana")))
+	})
+	It("returns error if the context is canceled", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := trigger.Wait(ctx)
+		Expect(err).To(MatchError(context.Canceled))
+	})
+	It("works with Triggered", func() {
+		var called bool
+		fn := run.Triggered(func(ctx context.Context) error {
+			called = true
+			return nil
+		}, trigger.Done())
+		trigger.Fire("banana")
+		Expect(fn(ctx)).To(Succeed())
+		Expect(called).To(BeTrue())
+	})
+	It("works with MultiTrigger", func() {
+		group := run.NewMultiTrigger()
+		address := run.NewValueTriggerWithTrigger[string](group.Add())
+		port := run.NewValueTriggerWithTrigger[int](group.Add())
+		address.Fire("localhost")
+		Expect(group.Done()).NotTo(BeClosed())
+		port.Fire(8080)
+		Expect(group.Done()).To(BeClosed())
+	})
+})
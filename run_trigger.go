@@ -12,6 +12,15 @@ type Fire interface {
 	Fire()
 }
 
+// FireFunc is a function type that implements the Fire interface.
+// It allows converting simple functions into Fire implementations.
+type FireFunc func()
+
+// Fire calls the function, implementing the Fire interface.
+func (f FireFunc) Fire() {
+	f()
+}
+
 // Done represents the ability to wait for a trigger event.
 type Done interface {
 	// Done returns a channel that receives a signal when the trigger is fired.
@@ -81,3 +81,14 @@ var _ = Describe("Trigger", func() {
 		Expect(counter).To(Equal(int64(10)))
 	})
 })
+
+var _ = Describe("FireFunc", func() {
+	It("calls the function on fire", func() {
+		counter := 0
+		var fire run.Fire = run.FireFunc(func() {
+			counter++
+		})
+		fire.Fire()
+		Expect(counter).To(Equal(1))
+	})
+})
@@ -20,3 +20,22 @@ func Triggered(fn Func, trigger <-chan struct{}) Func {
 		}
 	}
 }
+
+// TriggeredLoop runs the given function on every fire of the trigger until the context ends.
+// The trigger is reset before each run, so fires during a run cause exactly one further run.
+// If the trigger already fired, the first run starts immediately. An error of the function ends the loop.
+func TriggeredLoop(fn Func, trigger ResettableTrigger) Func {
+	return func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-trigger.Done():
+				trigger.Reset()
+				if err := fn(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sync"
+)
+
+// WaitGroup waits for a collection of tasks to finish like sync.WaitGroup,
+// but its Wait respects the context and its Done channel is closed while the counter is zero.
+type WaitGroup interface {
+	Done
+	// Add adds delta, which may be negative, to the counter. It panics if the counter becomes negative.
+	Add(delta int)
+	// Finish decrements the counter by one.
+	Finish()
+	// Go runs the function in a new goroutine and tracks it in the counter.
+	Go(fn func())
+	// Wait waits until the counter is zero or the context is canceled.
+	Wait(ctx context.Context) error
+}
+
+// NewWaitGroup creates a new WaitGroup with counter zero.
+func NewWaitGroup() WaitGroup {
+	done := make(chan struct{})
+	close(done)
+	return &waitGroup{
+		done: done,
+	}
+}
+
+type waitGroup struct {
+	mux     sync.Mutex
+	counter int
+	done    chan struct{}
+}
+
+func (w *waitGroup) Add(delta int) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	counter := w.counter + delta
+	if counter < 0 {
+		panic("run: negative WaitGroup counter")
+	}
+	if w.counter == 0 && counter > 0 {
+		w.done = make(chan struct{})
+	}
+	if w.counter > 0 && counter == 0 {
+		close(w.done)
+	}
+	w.counter = counter
+}
+
+func (w *waitGroup) Finish() {
+	w.Add(-1)
+}
+
+func (w *waitGroup) Go(fn func()) {
+	w.Add(1)
+	goLabeled(context.Background(), "WaitGroup", func() {
+		defer w.Finish()
+		fn()
+	})
+}
+
+func (w *waitGroup) Done() <-chan struct{} {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.done
+}
+
+func (w *waitGroup) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.Done():
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+)
+
+var _ = Describe("WaitGroup", func() {
+	var ctx context.Context
+	var wg run.WaitGroup
+	BeforeEach(func() {
+		ctx = context.Background()
+		wg = run.NewWaitGroup()
+	})
+	It("is done with counter zero", func() {
+		Expect(wg.Done()).To(BeClosed())
+		Expect(wg.Wait(ctx)).To(Succeed())
+	})
+	It("is done once all added tasks finished", func() {
+		wg.Add(2)
+		Expect(wg.Done()).NotTo(BeClosed())
+		wg.Finish()
+		Expect(wg.Done()).NotTo(BeClosed())
+		wg.Finish()
+		Expect(wg.Done()).To(BeClosed())
+	})
+	It("waits for functions started with Go", func() {
+		release := make(chan struct{})
+		wg.Go(func() {
+			<-release
+		})
+		done := wg.Done()
+		Expect(done).NotTo(BeClosed())
+		close(release)
+		Eventually(done).Should(BeClosed())
+		Expect(wg.Wait(ctx)).To(Succeed())
+	})
+	It("stops waiting if the context is canceled", func() {
+		wg.Add(1)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(wg.Wait(ctx)).To(MatchError(context.Canceled))
+	})
+	It("panics on negative counter", func() {
+		Expect(func() { wg.Finish() }).To(PanicWith("run: negative WaitGroup counter"))
+	})
+})
@@ -7,8 +7,6 @@ package run
 import (
 	"context"
 	"time"
-
-	"github.com/golang/glog"
 )
 
 //counterfeiter:generate -o mocks/waiter.go --fake-name Waiter . Waiter
@@ -30,12 +28,43 @@ func (w WaiterFunc) Wait(ctx context.Context, wait time.Duration) error {
 // NewWaiter creates a default Waiter implementation that uses time.Timer for delays.
 // The waiter respects context cancellation and logs the wait duration.
 func NewWaiter() Waiter {
+	return NewWaiterWithOptions(WaiterOptions{})
+}
+
+// NewWaiterWithLogger creates a Waiter like NewWaiter that logs the wait duration with the given logger.
+// If logger is nil, the DefaultLogger is used.
+func NewWaiterWithLogger(logger Logger) Waiter {
+	return NewWaiterWithOptions(WaiterOptions{
+		Logger: logger,
+	})
+}
+
+// NewWaiterWithClock creates a Waiter like NewWaiter that waits for timers of the given clock.
+// Together with a FakeClock it allows tests to control waits without sleeping.
+func NewWaiterWithClock(clock Clock) Waiter {
+	return NewWaiterWithOptions(WaiterOptions{
+		Clock: clock,
+	})
+}
+
+// WaiterOptions configures NewWaiterWithOptions.
+type WaiterOptions struct {
+	// Logger is used for logging. If nil, the DefaultLogger is used.
+	Logger Logger
+	// Clock provides the timers. If nil, the DefaultClock is used.
+	Clock Clock
+}
+
+// NewWaiterWithOptions creates a Waiter like NewWaiter configured by the given options.
+func NewWaiterWithOptions(options WaiterOptions) Waiter {
 	return WaiterFunc(func(ctx context.Context, wait time.Duration) error {
-		glog.V(3).Infof("sleep for %v", wait)
+		orDefaultLogger(options.Logger).Debug(ctx, "sleep", "duration", wait)
+		timer := orDefaultClock(options.Clock).NewTimer(wait)
+		defer timer.Stop()
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.NewTimer(wait).C:
+		case <-timer.C():
 			return nil
 		}
 	})
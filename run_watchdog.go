@@ -0,0 +1,254 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bborbe/errors"
+	"github.com/golang/glog"
+)
+
+// WatchdogReporter is notified when a watched function becomes stale and when it recovers.
+type WatchdogReporter interface {
+	// Stale is called once when the function did not succeed within its expected interval.
+	// lastSuccess is zero if the function never succeeded.
+	Stale(ctx context.Context, name string, lastSuccess time.Time, interval time.Duration)
+	// Recovered is called when a stale function succeeded again.
+	Recovered(ctx context.Context, name string)
+}
+
+// Watchdog tracks the last success of named functions and reports functions
+// that exceed their expected success interval.
+type Watchdog interface {
+	// Watch wraps a function whose successes are tracked under the given name.
+	// The function is expected to succeed at least once per interval.
+	Watch(name string, interval time.Duration, fn Func) Func
+	// Check reports all functions that became stale since the last check.
+	Check(ctx context.Context)
+	// Run checks all functions every check interval until the context is canceled.
+	Run(ctx context.Context) error
+}
+
+// NewWatchdog creates a Watchdog that checks the watched functions every checkInterval
+// and notifies all reporters about stale and recovered functions.
+func NewWatchdog(checkInterval time.Duration, reporters ...WatchdogReporter) Watchdog {
+	return NewWatchdogWithClock(nil, checkInterval, reporters...)
+}
+
+// NewWatchdogWithClock creates a Watchdog like NewWatchdog that uses the given clock.
+// If clock is nil, the DefaultClock is used.
+func NewWatchdogWithClock(
+	clock Clock,
+	checkInterval time.Duration,
+	reporters ...WatchdogReporter,
+) Watchdog {
+	return &watchdog{
+		clock:         orDefaultClock(clock),
+		checkInterval: checkInterval,
+		reporters:     reporters,
+		entries:       map[string]*watchdogEntry{},
+	}
+}
+
+type watchdogEntry struct {
+	interval    time.Duration
+	since       time.Time
+	lastSuccess time.Time
+	stale       bool
+}
+
+type watchdog struct {
+	clock         Clock
+	checkInterval time.Duration
+	reporters     []WatchdogReporter
+
+	mux     sync.Mutex
+	entries map[string]*watchdogEntry
+}
+
+func (w *watchdog) Watch(name string, interval time.Duration, fn Func) Func {
+	w.mux.Lock()
+	w.entries[name] = &watchdogEntry{
+		interval: interval,
+		since:    w.clock.Now(),
+	}
+	w.mux.Unlock()
+
+	return func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		w.mux.Lock()
+		entry := w.entries[name]
+		entry.lastSuccess = w.clock.Now()
+		recovered := entry.stale
+		entry.stale = false
+		w.mux.Unlock()
+
+		if recovered {
+			glog.V(2).Infof("watchdog %s recovered", name)
+			for _, reporter := range w.reporters {
+				reporter.Recovered(ctx, name)
+			}
+		}
+		return nil
+	}
+}
+
+func (w *watchdog) Check(ctx context.Context) {
+	type staleEntry struct {
+		name        string
+		lastSuccess time.Time
+		interval    time.Duration
+	}
+	var staleEntries []staleEntry
+
+	w.mux.Lock()
+	now := w.clock.Now()
+	for name, entry := range w.entries {
+		last := entry.lastSuccess
+		if last.IsZero() {
+			last = entry.since
+		}
+		if entry.stale || now.Sub(last) <= entry.interval {
+			continue
+		}
+		entry.stale = true
+		staleEntries = append(staleEntries, staleEntry{
+			name:        name,
+			lastSuccess: entry.lastSuccess,
+			interval:    entry.interval,
+		})
+	}
+	w.mux.Unlock()
+
+	sort.Slice(staleEntries, func(i, j int) bool {
+		return staleEntries[i].name < staleEntries[j].name
+	})
+	for _, entry := range staleEntries {
+		glog.V(2).Infof("watchdog %s stale", entry.name)
+		for _, reporter := range w.reporters {
+			reporter.Stale(ctx, entry.name, entry.lastSuccess, entry.interval)
+		}
+	}
+}
+
+func (w *watchdog) Run(ctx context.Context) error {
+	timer := w.clock.NewTimer(w.checkInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C():
+			w.Check(ctx)
+			timer.Reset(w.checkInterval)
+		}
+	}
+}
+
+// NewWatchdogLogReporter creates a WatchdogReporter that logs stale functions as warnings.
+func NewWatchdogLogReporter() WatchdogReporter {
+	return NewWatchdogLogReporterWithLogger(nil)
+}
+
+// NewWatchdogLogReporterWithLogger creates a WatchdogReporter like NewWatchdogLogReporter
+// that logs with the given logger. If logger is nil, the DefaultLogger is used.
+func NewWatchdogLogReporterWithLogger(logger Logger) WatchdogReporter {
+	return &watchdogLogReporter{
+		logger: logger,
+	}
+}
+
+type watchdogLogReporter struct {
+	logger Logger
+}
+
+func (w *watchdogLogReporter) Stale(
+	ctx context.Context,
+	name string,
+	lastSuccess time.Time,
+	interval time.Duration,
+) {
+	orDefaultLogger(w.logger).Warn(
+		ctx,
+		"watchdog stale",
+		"func", name,
+		"interval", interval,
+		"lastSuccess", lastSuccess,
+	)
+}
+
+func (w *watchdogLogReporter) Recovered(ctx context.Context, name string) {
+	orDefaultLogger(w.logger).Info(ctx, "watchdog recovered", "func", name)
+}
+
+// NewWatchdogSentryReporter creates a WatchdogReporter that reports stale functions to the error tracker.
+// The tags are attached to each report together with the name, interval and last success of the function.
+func NewWatchdogSentryReporter(
+	hasCaptureException HasCaptureException,
+	tags map[string]string,
+) WatchdogReporter {
+	return &watchdogSentryReporter{
+		reporter: NewSentryReporter(hasCaptureException),
+		tags:     tags,
+	}
+}
+
+type watchdogSentryReporter struct {
+	reporter Reporter
+	tags     map[string]string
+}
+
+func (w *watchdogSentryReporter) Stale(
+	ctx context.Context,
+	name string,
+	lastSuccess time.Time,
+	interval time.Duration,
+) {
+	w.reporter.Report(
+		ctx,
+		errors.AddDataToError(
+			errors.Errorf(ctx, "%s had no success within %v", name, interval),
+			map[string]string{
+				"name":        name,
+				"interval":    interval.String(),
+				"lastSuccess": lastSuccess.Format(time.RFC3339),
+			},
+		),
+		w.tags,
+	)
+}
+
+func (w *watchdogSentryReporter) Recovered(ctx context.Context, name string) {}
+
+// NewWatchdogHealthReporter creates a WatchdogReporter that marks stale functions
+// as not live in the health registry and live again once they recovered.
+func NewWatchdogHealthReporter(healthRegistry HealthRegistry) WatchdogReporter {
+	return &watchdogHealthReporter{
+		healthRegistry: healthRegistry,
+	}
+}
+
+type watchdogHealthReporter struct {
+	healthRegistry HealthRegistry
+}
+
+func (w *watchdogHealthReporter) Stale(
+	ctx context.Context,
+	name string,
+	lastSuccess time.Time,
+	interval time.Duration,
+) {
+	w.healthRegistry.SetLive(name, errors.Errorf(ctx, "no success within %v", interval))
+}
+
+func (w *watchdogHealthReporter) Recovered(ctx context.Context, name string) {
+	w.healthRegistry.SetLive(name, nil)
+}
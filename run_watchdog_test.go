@@ -0,0 +1,180 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package run_test
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/mocks"
+)
+
+type watchdogReport struct {
+	name  string
+	stale bool
+}
+
+type recordingWatchdogReporter struct {
+	mux     sync.Mutex
+	reports []watchdogReport
+}
+
+func (r *recordingWatchdogReporter) Stale(
+	ctx context.Context,
+	name string,
+	lastSuccess time.Time,
+	interval time.Duration,
+) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.reports = append(r.reports, watchdogReport{name: name, stale: true})
+}
+
+func (r *recordingWatchdogReporter) Recovered(ctx context.Context, name string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.reports = append(r.reports, watchdogReport{name: name, stale: false})
+}
+
+func (r *recordingWatchdogReporter) Reports() []watchdogReport {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]watchdogReport{}, r.reports...)
+}
+
+var _ = Describe("Watchdog", func() {
+	var ctx context.Context
+	var clock run.FakeClock
+	var reporter *recordingWatchdogReporter
+	var watchdog run.Watchdog
+	var result error
+	var fn run.Func
+	BeforeEach(func() {
+		ctx = context.Background()
+		clock = run.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		reporter = &recordingWatchdogReporter{}
+		watchdog = run.NewWatchdogWithClock(clock, time.Second, reporter)
+		result = nil
+		fn = watchdog.Watch("job", time.Minute, func(ctx context.Context) error {
+			return result
+		})
+	})
+	It("reports nothing within the interval", func() {
+		clock.Advance(time.Minute)
+		Expect(fn(ctx)).To(BeNil())
+		clock.Advance(time.Minute)
+		watchdog.Check(ctx)
+		Expect(reporter.Reports()).To(BeEmpty())
+	})
+	It("reports a function that never succeeded once", func() {
+		clock.Advance(time.Minute + time.Second)
+		watchdog.Check(ctx)
+		watchdog.Check(ctx)
+		Expect(reporter.Reports()).To(Equal([]watchdogReport{{name: "job", stale: true}}))
+	})
+	It("reports a function that only fails", func() {
+		result = stderrors.New("banana")
+		clock.Advance(time.Minute + time.Second)
+		Expect(fn(ctx)).To(Equal(result))
+		watchdog.Check(ctx)
+		Expect(reporter.Reports()).To(Equal([]watchdogReport{{name: "job", stale: true}}))
+	})
+	It("clears the alert on recovery", func() {
+		clock.Advance(time.Minute + time.Second)
+		watchdog.Check(ctx)
+		Expect(fn(ctx)).To(BeNil())
+		watchdog.Check(ctx)
+		Expect(reporter.Reports()).To(Equal([]watchdogReport{
+			{name: "job", stale: true},
+			{name: "job", stale: false},
+		}))
+	})
+	It("checks periodically until canceled", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- watchdog.Run(ctx)
+		}()
+		Eventually(clock.PendingTimers).Should(Equal(1))
+		clock.Advance(30 * time.Second)
+		Consistently(reporter.Reports, 50*time.Millisecond).Should(BeEmpty())
+		for i := 0; i < 31; i++ {
+			Eventually(clock.PendingTimers).Should(Equal(1))
+			clock.Advance(time.Second)
+		}
+		Eventually(reporter.Reports).Should(Equal([]watchdogReport{{name: "job", stale: true}}))
+		cancel()
+		Eventually(done).Should(Receive(Equal(context.Canceled)))
+	})
+	It("marks the component not live in the health registry", func() {
+		healthRegistry := run.NewHealthRegistry()
+		watchdog = run.NewWatchdogWithClock(
+			clock,
+			time.Second,
+			run.NewWatchdogHealthReporter(healthRegistry),
+		)
+		fn = watchdog.Watch("job", time.Minute, func(ctx context.Context) error {
+			return nil
+		})
+		clock.Advance(time.Minute + time.Second)
+		watchdog.Check(ctx)
+		Expect(healthRegistry.Status().Live).To(BeFalse())
+		Expect(fn(ctx)).To(BeNil())
+		Expect(healthRegistry.Status().Live).To(BeTrue())
+	})
+	It("reports stale functions to sentry", func() {
+		hasCaptureException := &mocks.HasCaptureException{}
+		watchdog = run.NewWatchdogWithClock(
+			clock,
+			time.Second,
+			run.NewWatchdogSentryReporter(hasCaptureException, map[string]string{"env": "test"}),
+		)
+		watchdog.Watch("job", time.Minute, func(ctx context.Context) error {
+			return nil
+		})
+		clock.Advance(time.Minute + time.Second)
+		watchdog.Check(ctx)
+		Expect(hasCaptureException.CaptureExceptionCallCount()).To(Equal(1))
+		err, hint, scope := hasCaptureException.CaptureExceptionArgsForCall(0)
+		Expect(err.Error()).To(ContainSubstring("job had no success within 1m0s"))
+		Expect(hint.Data).To(HaveKeyWithValue("env", "test"))
+		Expect(hint.Data).To(HaveKeyWithValue("name", "job"))
+		Expect(hint.Data).To(HaveKeyWithValue("interval", "1m0s"))
+		event := scope.ApplyToEvent(&sentry.Event{}, hint, nil)
+		Expect(event.Tags).To(HaveKeyWithValue("env", "test"))
+		Expect(event.Tags).To(HaveKeyWithValue("name", "job"))
+	})
+	It("logs stale and recovered functions", func() {
+		buf := &syncBuffer{}
+		watchdog = run.NewWatchdogWithClock(
+			clock,
+			time.Second,
+			run.NewWatchdogLogReporterWithLogger(
+				run.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, nil))),
+			),
+		)
+		fn = watchdog.Watch("job", time.Minute, func(ctx context.Context) error {
+			return nil
+		})
+		clock.Advance(time.Minute + time.Second)
+		watchdog.Check(ctx)
+		Expect(fn(ctx)).To(BeNil())
+		records := buf.Records()
+		Expect(records).To(HaveLen(2))
+		Expect(records[0]).To(HaveKeyWithValue("msg", "watchdog stale"))
+		Expect(records[0]).To(HaveKeyWithValue("level", "WARN"))
+		Expect(records[0]).To(HaveKeyWithValue("func", "job"))
+		Expect(records[1]).To(HaveKeyWithValue("msg", "watchdog recovered"))
+		Expect(records[1]).To(HaveKeyWithValue("func", "job"))
+	})
+})
@@ -0,0 +1,13 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package runtest provides test helpers for code built on github.com/bborbe/run.
+//
+// It offers controllable fake functions that block until released, return scripted
+// results and record their calls, a recording Waiter, and Gomega matchers for Ginkgo v2:
+//
+//	fn := runtest.NewFakeFunc().FailTimes(2, errors.New("banana"))
+//	Expect(run.Retry(backoff, fn.Func())).To(runtest.SucceedWithin(time.Second))
+//	Expect(fn).To(runtest.BeCalledTimes(3))
+package runtest
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bborbe/run"
+)
+
+// Call is a recorded call of a FakeFunc.
+type Call struct {
+	// Ctx is the context the function was called with.
+	Ctx context.Context
+	// Start is the time the call started.
+	Start time.Time
+	// End is the time the call returned, zero while it is running.
+	End time.Time
+	// Err is the error returned by the call.
+	Err error
+	// Canceled is true if the context was canceled when the call returned.
+	Canceled bool
+}
+
+// FakeFunc is a controllable run.Func for tests.
+// It can block until released, return scripted results and records all calls.
+type FakeFunc struct {
+	mux       sync.Mutex
+	blocked   bool
+	release   chan struct{}
+	results   []error
+	result    error
+	calls     []*Call
+	running   int
+	callAdded chan struct{}
+}
+
+// NewFakeFunc creates a FakeFunc that returns nil without blocking.
+func NewFakeFunc() *FakeFunc {
+	return &FakeFunc{
+		release:   make(chan struct{}),
+		callAdded: make(chan struct{}),
+	}
+}
+
+// Func returns the function to pass to the code under test.
+func (f *FakeFunc) Func() run.Func {
+	return f.Run
+}
+
+// Run executes the fake. It records the call, blocks while the fake is blocked
+// and returns the next scripted result.
+func (f *FakeFunc) Run(ctx context.Context) error {
+	f.mux.Lock()
+	call := &Call{
+		Ctx:   ctx,
+		Start: time.Now(),
+	}
+	f.calls = append(f.calls, call)
+	f.running++
+	close(f.callAdded)
+	f.callAdded = make(chan struct{})
+	blocked := f.blocked
+	release := f.release
+	f.mux.Unlock()
+
+	var err error
+	if blocked {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-release:
+		}
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if err == nil {
+		err = f.nextResult()
+	}
+	f.running--
+	call.End = time.Now()
+	call.Err = err
+	call.Canceled = ctx.Err() != nil
+	return err
+}
+
+// nextResult returns the next scripted result, the caller must hold the lock.
+func (f *FakeFunc) nextResult() error {
+	if len(f.results) == 0 {
+		return f.result
+	}
+	result := f.results[0]
+	f.results = f.results[1:]
+	return result
+}
+
+// Returns sets the result returned once all scripted results are consumed.
+func (f *FakeFunc) Returns(err error) *FakeFunc {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.result = err
+	return f
+}
+
+// Results scripts the results of the next calls, one result per call.
+func (f *FakeFunc) Results(results ...error) *FakeFunc {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.results = append(f.results, results...)
+	return f
+}
+
+// FailTimes scripts the next n calls to return err, the calls afterwards return the default result.
+func (f *FakeFunc) FailTimes(n int, err error) *FakeFunc {
+	results := make([]error, n)
+	for i := range results {
+		results[i] = err
+	}
+	return f.Results(results...)
+}
+
+// Block makes all following calls block until Release is called or their context is canceled.
+// A canceled call returns the error of the context.
+func (f *FakeFunc) Block() *FakeFunc {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.blocked = true
+	return f
+}
+
+// Release unblocks all calls waiting and all following calls.
+func (f *FakeFunc) Release() {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if !f.blocked {
+		return
+	}
+	f.blocked = false
+	close(f.release)
+	f.release = make(chan struct{})
+}
+
+// CallCount returns the number of calls started so far.
+func (f *FakeFunc) CallCount() int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return len(f.calls)
+}
+
+// Calls returns copies of all recorded calls in the order they started.
+func (f *FakeFunc) Calls() []Call {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	result := make([]Call, len(f.calls))
+	for i, call := range f.calls {
+		result[i] = *call
+	}
+	return result
+}
+
+// Running returns the number of calls currently running.
+func (f *FakeFunc) Running() int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.running
+}
+
+// WaitForCalls blocks until at least n calls started or the context is canceled.
+func (f *FakeFunc) WaitForCalls(ctx context.Context, n int) error {
+	for {
+		f.mux.Lock()
+		count := len(f.calls)
+		callAdded := f.callAdded
+		f.mux.Unlock()
+		if count >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-callAdded:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/runtest"
+)
+
+var _ = Describe("FakeFunc", func() {
+	var ctx context.Context
+	var fake *runtest.FakeFunc
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = runtest.NewFakeFunc()
+	})
+	It("returns nil by default", func() {
+		Expect(fake.Func()(ctx)).To(Succeed())
+		Expect(fake).To(runtest.BeCalledTimes(1))
+	})
+	It("returns scripted results", func() {
+		banana := stderrors.New("banana")
+		apple := stderrors.New("apple")
+		fake.Results(banana, nil).Returns(apple)
+		Expect(fake.Run(ctx)).To(Equal(banana))
+		Expect(fake.Run(ctx)).To(BeNil())
+		Expect(fake.Run(ctx)).To(Equal(apple))
+		Expect(fake.Run(ctx)).To(Equal(apple))
+	})
+	It("fails n times before it succeeds", func() {
+		fake.FailTimes(2, stderrors.New("banana"))
+		fn := run.RetryWaiter(run.Backoff{Retries: 3}, runtest.NewRecordingWaiter(), fake.Func())
+		Expect(fn(ctx)).To(Succeed())
+		Expect(fake).To(runtest.BeCalledTimes(3))
+	})
+	It("records calls", func() {
+		Expect(fake.Run(ctx)).To(Succeed())
+		calls := fake.Calls()
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].Ctx).To(Equal(ctx))
+		Expect(calls[0].End).NotTo(BeTemporally("<", calls[0].Start))
+		Expect(calls[0]).NotTo(runtest.BeCancelled())
+	})
+	It("blocks until released", func() {
+		fake.Block()
+		done := make(chan error, 1)
+		go func() {
+			done <- fake.Run(ctx)
+		}()
+		Expect(fake.WaitForCalls(ctx, 1)).To(Succeed())
+		Expect(fake.Running()).To(Equal(1))
+		Consistently(done, 20*time.Millisecond).ShouldNot(Receive())
+		fake.Release()
+		Expect((<-chan error)(done)).To(runtest.SucceedWithin(time.Second))
+		Expect(fake.Running()).To(Equal(0))
+	})
+	It("returns when the blocked call is canceled", func() {
+		fake.Block()
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- fake.Run(ctx)
+		}()
+		Expect(fake.WaitForCalls(ctx, 1)).To(Succeed())
+		cancel()
+		Eventually(done).Should(Receive(runtest.BeCancelled()))
+		Expect(fake).To(runtest.BeCancelled())
+	})
+	It("stops waiting for calls on cancel", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		Expect(fake.WaitForCalls(ctx, 1)).To(runtest.BeCancelled())
+	})
+})
@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bborbe/run"
+)
+
+// Goroutine is a group of goroutines with identical stack spawned by a run primitive.
+type Goroutine struct {
+	// Count is the number of goroutines in the group.
+	Count int
+	// Primitive is the value of the run.GoroutineLabel, e.g. "Run" or "BackgroundRunner".
+	Primitive string
+	// Stack is the symbolized stack shared by all goroutines of the group.
+	Stack string
+}
+
+// LeakDetector finds goroutines spawned by run primitives that are still alive.
+// Goroutines already running when the detector was created are ignored.
+//
+//	var detector *runtest.LeakDetector
+//	BeforeEach(func() { detector = runtest.NewLeakDetector() })
+//	AfterEach(func() { Expect(detector.Check(time.Second)).To(Succeed()) })
+type LeakDetector struct {
+	baseline map[string]int
+}
+
+// NewLeakDetector creates a LeakDetector with a snapshot of the currently running goroutines.
+func NewLeakDetector() *LeakDetector {
+	baseline := map[string]int{}
+	for _, goroutine := range labeledGoroutines() {
+		baseline[goroutine.Stack] += goroutine.Count
+	}
+	return &LeakDetector{
+		baseline: baseline,
+	}
+}
+
+// Leaks returns the goroutines spawned by run primitives since the snapshot that are still alive.
+func (l *LeakDetector) Leaks() []Goroutine {
+	var result []Goroutine
+	for _, goroutine := range labeledGoroutines() {
+		goroutine.Count -= l.baseline[goroutine.Stack]
+		if goroutine.Count > 0 {
+			result = append(result, goroutine)
+		}
+	}
+	return result
+}
+
+// Check waits up to settle for all goroutines spawned since the snapshot to exit.
+// It returns an error listing the stacks of the goroutines still alive afterwards.
+func (l *LeakDetector) Check(settle time.Duration) error {
+	deadline := time.Now().Add(settle)
+	for {
+		leaks := l.Leaks()
+		if len(leaks) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return newLeakError(settle, leaks)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newLeakError(settle time.Duration, leaks []Goroutine) error {
+	var total int
+	var buf strings.Builder
+	for _, leak := range leaks {
+		total += leak.Count
+		fmt.Fprintf(
+			&buf,
+			"\n\n%d goroutine(s) spawned by %s:\n%s",
+			leak.Count,
+			leak.Primitive,
+			leak.Stack,
+		)
+	}
+	return fmt.Errorf("%d goroutine(s) leaked after %v:%s", total, settle, buf.String())
+}
+
+// labeledGoroutines parses the goroutine profile and returns all groups labeled by run primitives.
+func labeledGoroutines() []Goroutine {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+	var result []Goroutine
+	for _, block := range strings.Split(buf.String(), "\n\n") {
+		goroutine, ok := parseGoroutine(block)
+		if ok {
+			result = append(result, goroutine)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Stack < result[j].Stack
+	})
+	return result
+}
+
+// parseGoroutine parses a group of the goroutine profile in debug format 1:
+//
+//	2 @ 0x43a8d6 0x4079ed
+//	# labels: {"run":"Run"}
+//	#	0x43a8d5	runtime.gopark+0x115	/usr/lib/go/src/runtime/proc.go:435
+func parseGoroutine(block string) (Goroutine, bool) {
+	lines := strings.Split(strings.TrimSpace(block), "\n")
+	header := strings.SplitN(lines[0], " @ ", 2)
+	if len(header) != 2 {
+		return Goroutine{}, false
+	}
+	count, err := strconv.Atoi(header[0])
+	if err != nil {
+		return Goroutine{}, false
+	}
+	var primitive string
+	var stack []string
+	for _, line := range lines[1:] {
+		if labels, ok := strings.CutPrefix(line, "# labels: "); ok {
+			values := map[string]string{}
+			if err := json.Unmarshal([]byte(labels), &values); err == nil {
+				primitive = values[run.GoroutineLabel]
+			}
+			continue
+		}
+		stack = append(stack, strings.TrimPrefix(line, "#"))
+	}
+	if primitive == "" {
+		return Goroutine{}, false
+	}
+	return Goroutine{
+		Count:     count,
+		Primitive: primitive,
+		Stack:     strings.Join(stack, "\n"),
+	}, true
+}
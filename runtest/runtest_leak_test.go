@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/runtest"
+)
+
+var _ = Describe("LeakDetector", func() {
+	var ctx context.Context
+	var detector *runtest.LeakDetector
+	var release chan struct{}
+	BeforeEach(func() {
+		ctx = context.Background()
+		release = make(chan struct{})
+		detector = runtest.NewLeakDetector()
+	})
+	It("succeeds without goroutines spawned", func() {
+		Expect(detector.Check(0)).To(Succeed())
+	})
+	It("succeeds if spawned goroutines exit", func() {
+		err := run.All(ctx, func(ctx context.Context) error { return nil })
+		Expect(err).To(BeNil())
+		Expect(detector.Check(time.Second)).To(Succeed())
+	})
+	Context("with func ignoring cancellation", func() {
+		BeforeEach(func() {
+			release := release
+			err := run.CancelOnFirstFinish(
+				ctx,
+				func(ctx context.Context) error { return nil },
+				func(ctx context.Context) error {
+					<-release
+					return nil
+				},
+			)
+			Expect(err).To(BeNil())
+		})
+		It("reports the leaked goroutine with its stack", func() {
+			err := detector.Check(50 * time.Millisecond)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("spawned by Run"))
+			Expect(err.Error()).To(ContainSubstring("runtest_leak_test.go"))
+			leaks := detector.Leaks()
+			Expect(leaks).NotTo(BeEmpty())
+			Expect(leaks[0].Primitive).To(Equal("Run"))
+			close(release)
+		})
+		It("succeeds once the goroutine exits within the settle period", func() {
+			time.AfterFunc(20*time.Millisecond, func() { close(release) })
+			Expect(detector.Check(time.Second)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+
+	"github.com/bborbe/run"
+)
+
+// SucceedWithin succeeds if the actual function returns nil within the timeout.
+// The actual value must be a run.Func, a func(context.Context) error or a <-chan error.
+// The function is called with a context that is canceled after the timeout.
+func SucceedWithin(timeout time.Duration) types.GomegaMatcher {
+	return &succeedWithinMatcher{
+		timeout: timeout,
+	}
+}
+
+type succeedWithinMatcher struct {
+	timeout  time.Duration
+	timedOut bool
+	err      error
+}
+
+func (s *succeedWithinMatcher) Match(actual interface{}) (bool, error) {
+	var results <-chan error
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	switch fn := actual.(type) {
+	case run.Func:
+		results = callAsync(ctx, fn)
+	case func(context.Context) error:
+		results = callAsync(ctx, fn)
+	case <-chan error:
+		results = fn
+	case chan error:
+		results = fn
+	default:
+		return false, fmt.Errorf(
+			"SucceedWithin expects a run.Func, func(context.Context) error or <-chan error, got:\n%s",
+			format.Object(actual, 1),
+		)
+	}
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case s.err = <-results:
+		return s.err == nil, nil
+	case <-timer.C:
+		s.timedOut = true
+		return false, nil
+	}
+}
+
+func callAsync(ctx context.Context, fn run.Func) <-chan error {
+	results := make(chan error, 1)
+	go func() {
+		results <- fn(ctx)
+	}()
+	return results
+}
+
+func (s *succeedWithinMatcher) FailureMessage(actual interface{}) string {
+	if s.timedOut {
+		return fmt.Sprintf("Expected function to return within %v, but it did not", s.timeout)
+	}
+	return fmt.Sprintf(
+		"Expected function to succeed within %v, but it failed with:\n%s",
+		s.timeout,
+		format.Object(s.err, 1),
+	)
+}
+
+func (s *succeedWithinMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected function not to succeed within %v, but it did", s.timeout)
+}
+
+// CallCounter is implemented by fakes that count their calls, like FakeFunc.
+type CallCounter interface {
+	CallCount() int
+}
+
+// BeCalledTimes succeeds if the actual CallCounter was called exactly n times.
+func BeCalledTimes(n int) types.GomegaMatcher {
+	return &beCalledTimesMatcher{
+		expected: n,
+	}
+}
+
+type beCalledTimesMatcher struct {
+	expected int
+	actual   int
+}
+
+func (b *beCalledTimesMatcher) Match(actual interface{}) (bool, error) {
+	counter, ok := actual.(CallCounter)
+	if !ok {
+		return false, fmt.Errorf(
+			"BeCalledTimes expects a CallCounter, got:\n%s",
+			format.Object(actual, 1),
+		)
+	}
+	b.actual = counter.CallCount()
+	return b.actual == b.expected, nil
+}
+
+func (b *beCalledTimesMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected to be called %d times, but was called %d times", b.expected, b.actual)
+}
+
+func (b *beCalledTimesMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected not to be called %d times, but it was", b.expected)
+}
+
+// BeCancelled succeeds if the actual value was canceled. It accepts a context.Context,
+// an error matching context.Canceled, a Call or a FakeFunc whose last call was canceled.
+func BeCancelled() types.GomegaMatcher {
+	return &beCancelledMatcher{}
+}
+
+type beCancelledMatcher struct{}
+
+func (b *beCancelledMatcher) Match(actual interface{}) (bool, error) {
+	switch value := actual.(type) {
+	case context.Context:
+		return errors.Is(value.Err(), context.Canceled), nil
+	case error:
+		return errors.Is(value, context.Canceled), nil
+	case Call:
+		return value.Canceled, nil
+	case *FakeFunc:
+		calls := value.Calls()
+		if len(calls) == 0 {
+			return false, nil
+		}
+		return calls[len(calls)-1].Canceled, nil
+	case nil:
+		return false, nil
+	default:
+		return false, fmt.Errorf(
+			"BeCancelled expects a context.Context, error, Call or *FakeFunc, got:\n%s",
+			format.Object(actual, 1),
+		)
+	}
+}
+
+func (b *beCancelledMatcher) FailureMessage(actual interface{}) string {
+	return format.Message(actual, "to be cancelled")
+}
+
+func (b *beCancelledMatcher) NegatedFailureMessage(actual interface{}) string {
+	return format.Message(actual, "not to be cancelled")
+}
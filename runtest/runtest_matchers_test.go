@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest_test
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/runtest"
+)
+
+var _ = Describe("Matchers", func() {
+	Context("SucceedWithin", func() {
+		It("matches functions returning nil in time", func() {
+			Expect(run.Func(func(ctx context.Context) error {
+				return nil
+			})).To(runtest.SucceedWithin(time.Second))
+		})
+		It("does not match failing functions", func() {
+			Expect(func(ctx context.Context) error {
+				return stderrors.New("banana")
+			}).NotTo(runtest.SucceedWithin(time.Second))
+		})
+		It("does not match functions that do not return in time", func() {
+			fake := runtest.NewFakeFunc().Block()
+			defer fake.Release()
+			Expect(fake.Func()).NotTo(runtest.SucceedWithin(10 * time.Millisecond))
+		})
+		It("fails for unsupported values", func() {
+			_, err := runtest.SucceedWithin(time.Second).Match("banana")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Context("BeCalledTimes", func() {
+		It("matches the call count", func() {
+			fake := runtest.NewFakeFunc()
+			Expect(fake).To(runtest.BeCalledTimes(0))
+			_ = fake.Run(context.Background())
+			Expect(fake).To(runtest.BeCalledTimes(1))
+			Expect(fake).NotTo(runtest.BeCalledTimes(2))
+		})
+		It("fails for unsupported values", func() {
+			_, err := runtest.BeCalledTimes(1).Match("banana")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+	Context("BeCancelled", func() {
+		It("matches canceled contexts", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			Expect(ctx).NotTo(runtest.BeCancelled())
+			cancel()
+			Expect(ctx).To(runtest.BeCancelled())
+		})
+		It("matches canceled errors", func() {
+			Expect(context.Canceled).To(runtest.BeCancelled())
+			Expect(stderrors.New("banana")).NotTo(runtest.BeCancelled())
+		})
+		It("fails for unsupported values", func() {
+			_, err := runtest.BeCancelled().Match("banana")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bborbe/run"
+)
+
+// RecordingWaiter is a run.Waiter that returns immediately and records the requested waits.
+type RecordingWaiter struct {
+	mux   sync.Mutex
+	waits []time.Duration
+}
+
+var _ run.Waiter = &RecordingWaiter{}
+
+// NewRecordingWaiter creates a new RecordingWaiter.
+func NewRecordingWaiter() *RecordingWaiter {
+	return &RecordingWaiter{}
+}
+
+// Wait records the duration and returns the error of the context if it is canceled.
+func (r *RecordingWaiter) Wait(ctx context.Context, wait time.Duration) error {
+	r.mux.Lock()
+	r.waits = append(r.waits, wait)
+	r.mux.Unlock()
+	return ctx.Err()
+}
+
+// Waits returns all recorded durations in the order they were requested.
+func (r *RecordingWaiter) Waits() []time.Duration {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	result := make([]time.Duration, len(r.waits))
+	copy(result, r.waits)
+	return result
+}
+
+// Total returns the sum of all recorded durations.
+func (r *RecordingWaiter) Total() time.Duration {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var total time.Duration
+	for _, wait := range r.waits {
+		total += wait
+	}
+	return total
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Benjamin Borbe All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtest_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bborbe/run"
+	"github.com/bborbe/run/runtest"
+)
+
+var _ = Describe("RecordingWaiter", func() {
+	It("records the waits of a retry", func() {
+		waiter := runtest.NewRecordingWaiter()
+		fake := runtest.NewFakeFunc().FailTimes(3, context.DeadlineExceeded)
+		fn := run.RetryWaiter(
+			run.Backoff{Delay: time.Second, Factor: 1, Retries: 3},
+			waiter,
+			fake.Func(),
+		)
+		Expect(fn(context.Background())).To(Succeed())
+		Expect(waiter.Waits()).To(Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}))
+		Expect(waiter.Total()).To(Equal(6 * time.Second))
+	})
+	It("returns the error of a canceled context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(runtest.NewRecordingWaiter().Wait(ctx, time.Second)).To(runtest.BeCancelled())
+	})
+})